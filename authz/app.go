@@ -0,0 +1,332 @@
+package authz
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v47/github"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrInvalidPrivateKey    = errors.New("invalid GitHub App private key")
+	ErrNoInstallationTarget = errors.New("cannot determine the organization or the repository to find the installation")
+	ErrUnknownAppAuthMode   = errors.New("unknown GitHub App authentication mode")
+)
+
+const (
+	installationTokenLeeway  = time.Minute
+	installationTimeout      = time.Second * 10
+	appJWTLifetime           = time.Minute * 9
+	appJWTClockSkewAllowance = time.Minute
+)
+
+var appJWTHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+
+// AppAuthMode tells when the proxy uses GitHub App installation tokens instead of the caller's token.
+type AppAuthMode int
+
+const (
+	// AppAuthFallback uses installation tokens only if the incoming request has no token.
+	AppAuthFallback AppAuthMode = iota
+	// AppAuthAlways uses installation tokens regardless of the caller's token.
+	AppAuthAlways
+)
+
+// ParseAppAuthMode parses the name of AppAuthMode.
+func ParseAppAuthMode(s string) (AppAuthMode, error) {
+	switch s {
+	case "fallback":
+		return AppAuthFallback, nil
+	case "always":
+		return AppAuthAlways, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrUnknownAppAuthMode, s)
+	}
+}
+
+func (m AppAuthMode) String() string {
+	switch m {
+	case AppAuthFallback:
+		return "fallback"
+	case AppAuthAlways:
+		return "always"
+	default:
+		return strconv.Itoa(int(m))
+	}
+}
+
+// Uses reports whether the request bearing the authorization header should be authenticated as the installation.
+func (m AppAuthMode) Uses(authzHeader string) bool {
+	if m == AppAuthAlways {
+		return true
+	}
+	_, found := extractToken(authzHeader)
+	return !found
+}
+
+// AppOption configures App.
+type AppOption func(*App) error
+
+// WithAppAPIBaseURL makes App call GitHub Enterprise Server API at the URL.
+func WithAppAPIBaseURL(baseURL string) AppOption {
+	return func(a *App) error {
+		client, err := github.NewEnterpriseClient(baseURL, baseURL, a.client.Client())
+		if err != nil {
+			return err
+		}
+		a.client = client
+		return nil
+	}
+}
+
+// App authenticates upstream calls as GitHub App installations.
+//
+// It finds the installation from the organization or the repository in the request path, and mints the installation token.
+// Installation tokens are cached until shortly before they expire.
+// Installation IDs are cached until GitHub answers 404 to the token exchange, which means the app is reinstalled.
+type App struct {
+	id         int64
+	privateKey *rsa.PrivateKey
+	client     *github.Client
+
+	group         singleflight.Group
+	mux           sync.Mutex
+	installations map[string]int64
+	tokens        map[int64]*github.InstallationToken
+}
+
+// NewApp returns an App signs JWT with the PEM encoded private key.
+func NewApp(appID int64, privateKeyPEM []byte, opts ...AppOption) (*App, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	a := &App{
+		id:            appID,
+		privateKey:    key,
+		installations: map[string]int64{},
+		tokens:        map[int64]*github.InstallationToken{},
+	}
+	a.client = github.NewClient(&http.Client{Transport: &appJWTTransport{app: a, base: http.DefaultTransport}})
+	for _, opt := range opts {
+		if err := opt(a); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// ID returns the GitHub App ID.
+func (a *App) ID() int64 {
+	return a.id
+}
+
+// Transport returns http.RoundTripper that authenticates requests with the installation token of the organization or the repository the request is for.
+func (a *App) Transport(base http.RoundTripper) http.RoundTripper {
+	return &installationTransport{app: a, base: base}
+}
+
+func (a *App) signJWT(now time.Time) (string, error) {
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-appJWTClockSkewAllowance).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": a.id,
+	})
+	if err != nil {
+		return "", err
+	}
+	signingInput := appJWTHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, a.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (a *App) installationID(ctx context.Context, target installationTarget) (int64, error) {
+	key := target.String()
+	a.mux.Lock()
+	id, ok := a.installations[key]
+	a.mux.Unlock()
+	if ok {
+		return id, nil
+	}
+	v, err, _ := a.group.Do("installation:"+key, func() (any, error) {
+		var (
+			installation *github.Installation
+			err          error
+		)
+		if target.repo == "" {
+			installation, _, err = a.client.Apps.FindOrganizationInstallation(ctx, target.owner)
+		} else {
+			installation, _, err = a.client.Apps.FindRepositoryInstallation(ctx, target.owner, target.repo)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("find installation of %s: %w", key, err)
+		}
+		a.mux.Lock()
+		a.installations[key] = installation.GetID()
+		a.mux.Unlock()
+		return installation.GetID(), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int64), nil
+}
+
+// forgetInstallation evicts the installation ID of the target and its token unless the ID is already replaced.
+func (a *App) forgetInstallation(target installationTarget, installationID int64) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	if id, ok := a.installations[target.String()]; ok && id == installationID {
+		delete(a.installations, target.String())
+	}
+	delete(a.tokens, installationID)
+}
+
+func (a *App) installationToken(ctx context.Context, installationID int64) (string, error) {
+	a.mux.Lock()
+	tok, ok := a.tokens[installationID]
+	a.mux.Unlock()
+	if ok && time.Until(tok.GetExpiresAt()) > installationTokenLeeway {
+		return tok.GetToken(), nil
+	}
+	v, err, _ := a.group.Do("token:"+strconv.FormatInt(installationID, 10), func() (any, error) {
+		tok, _, err := a.client.Apps.CreateInstallationToken(ctx, installationID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create installation token of %d: %w", installationID, err)
+		}
+		a.mux.Lock()
+		a.tokens[installationID] = tok
+		a.mux.Unlock()
+		return tok.GetToken(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+type installationTarget struct {
+	owner string
+	repo  string
+}
+
+func (t installationTarget) String() string {
+	if t.repo == "" {
+		return t.owner
+	}
+	return t.owner + "/" + t.repo
+}
+
+// findInstallationTarget finds the organization or the repository from the REST API path such as /orgs/{org}/... or /repos/{owner}/{repo}/...
+func findInstallationTarget(path string) (installationTarget, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		switch segment {
+		case "orgs":
+			if i+1 < len(segments) && segments[i+1] != "" {
+				return installationTarget{owner: segments[i+1]}, true
+			}
+		case "repos":
+			if i+2 < len(segments) && segments[i+1] != "" && segments[i+2] != "" {
+				return installationTarget{owner: segments[i+1], repo: segments[i+2]}, true
+			}
+		}
+	}
+	return installationTarget{}, false
+}
+
+type installationTransport struct {
+	app  *App
+	base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*installationTransport)(nil)
+
+func (t *installationTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	target, ok := findInstallationTarget(r.URL.Path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoInstallationTarget, r.URL.Path)
+	}
+	// installations and tokens are shared across requests so they must not be bound to the context of this request
+	ctx, cancel := context.WithTimeout(context.Background(), installationTimeout)
+	defer cancel()
+	installationID, err := t.app.installationID(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	token, err := t.app.installationToken(ctx, installationID)
+	if isNotFound(err) {
+		// the app is uninstalled, and it may be installed again with another ID
+		t.app.forgetInstallation(target, installationID)
+		if installationID, err = t.app.installationID(ctx, target); err != nil {
+			return nil, err
+		}
+		token, err = t.app.installationToken(ctx, installationID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req := r.Clone(r.Context())
+	req.Header.Set("authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+type appJWTTransport struct {
+	app  *App
+	base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*appJWTTransport)(nil)
+
+func (t *appJWTTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	jwt, err := t.app.signJWT(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req := r.Clone(r.Context())
+	req.Header.Set("authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(req)
+}
+
+func isNotFound(err error) bool {
+	var errResp *github.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
+func parsePrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidPrivateKey)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPrivateKey, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not a RSA private key", ErrInvalidPrivateKey)
+	}
+	return key, nil
+}
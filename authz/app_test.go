@@ -0,0 +1,240 @@
+package authz_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApp_Transport(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	var (
+		mux          sync.Mutex
+		tokensMinted = map[string]int{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/installation"):
+			if err := verifyJWT(&key.PublicKey, r.Header.Get("authorization")); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintf(w, `{"message":%q}`, err.Error())
+				return
+			}
+			id := 42
+			if strings.HasPrefix(r.URL.Path, "/api/v3/repos/") {
+				id = 43
+			}
+			fmt.Fprintf(w, `{"id":%d}`, id)
+		case strings.HasSuffix(r.URL.Path, "/access_tokens"):
+			if err := verifyJWT(&key.PublicKey, r.Header.Get("authorization")); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprintf(w, `{"message":%q}`, err.Error())
+				return
+			}
+			id := strings.Split(r.URL.Path, "/")[5]
+			mux.Lock()
+			tokensMinted[id]++
+			mux.Unlock()
+			fmt.Fprintf(w, `{"token":"ghs_%s","expires_at":%q}`, id, time.Now().Add(time.Hour).Format(time.RFC3339))
+		default:
+			fmt.Fprintf(w, `{"authorization":%q}`, r.Header.Get("authorization"))
+		}
+	}))
+	defer srv.Close()
+	app, err := authz.NewApp(1234, keyPEM, authz.WithAppAPIBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: app.Transport(srv.Client().Transport)}
+
+	type testCase struct {
+		path      string
+		wantToken string
+		wantErr   error
+	}
+	testCases := []testCase{
+		{"/api/v3/orgs/test-org/settings/billing/actions", "Bearer ghs_42", nil},
+		{"/api/v3/orgs/test-org/settings/billing/shared-storage", "Bearer ghs_42", nil},
+		{"/api/v3/repos/test-org/test-repo/actions/artifacts", "Bearer ghs_43", nil},
+		{"/api/v3/meta", "", authz.ErrNoInstallationTarget},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: got=%v want=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer resp.Body.Close()
+			var body struct{ Authorization string }
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Authorization != tc.wantToken {
+				t.Errorf("authorization: got=%q want=%q", body.Authorization, tc.wantToken)
+			}
+		})
+	}
+	if diff := cmp.Diff(tokensMinted, map[string]int{"42": 1, "43": 1}); diff != "" {
+		t.Errorf("minted tokens (-got, +want):\n%s", diff)
+	}
+}
+
+func TestNewApp_invalidKey(t *testing.T) {
+	_, err := authz.NewApp(1234, []byte("not a key"))
+	if !errors.Is(err, authz.ErrInvalidPrivateKey) {
+		t.Errorf("error: got=%v want=%v", err, authz.ErrInvalidPrivateKey)
+	}
+}
+
+func TestAppAuthMode_Uses(t *testing.T) {
+	type testCase struct {
+		mode        authz.AppAuthMode
+		authzHeader string
+		want        bool
+	}
+	testCases := []testCase{
+		{authz.AppAuthFallback, "", true},
+		{authz.AppAuthFallback, "Bearer 0xdeadbeaf", false},
+		{authz.AppAuthAlways, "", true},
+		{authz.AppAuthAlways, "Bearer 0xdeadbeaf", true},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%s/%q", tc.mode, tc.authzHeader), func(t *testing.T) {
+			if got := tc.mode.Uses(tc.authzHeader); got != tc.want {
+				t.Errorf("got=%v want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func verifyJWT(key *rsa.PublicKey, authzHeader string) error {
+	jwt := strings.TrimPrefix(authzHeader, "Bearer ")
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed JWT: %q", jwt)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return err
+	}
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Iss int64 `json:"iss"`
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return err
+	}
+	if claims.Iss != 1234 {
+		return fmt.Errorf("unexpected iss: %d", claims.Iss)
+	}
+	if time.Unix(claims.Exp, 0).Before(time.Now()) {
+		return errors.New("expired JWT")
+	}
+	return nil
+}
+
+func TestApp_Transport_reinstalled(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	var (
+		mux            sync.Mutex
+		installationID = "42"
+		lookups        int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		mux.Lock()
+		defer mux.Unlock()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/installation"):
+			lookups++
+			fmt.Fprintf(w, `{"id":%s}`, installationID)
+		case strings.HasSuffix(r.URL.Path, "/access_tokens"):
+			id := strings.Split(r.URL.Path, "/")[5]
+			if id != installationID {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"message":"Not Found"}`)
+				return
+			}
+			// expires within the leeway so that every call mints a token
+			fmt.Fprintf(w, `{"token":"ghs_%s","expires_at":%q}`, id, time.Now().Add(time.Second*30).Format(time.RFC3339))
+		default:
+			fmt.Fprintf(w, `{"authorization":%q}`, r.Header.Get("authorization"))
+		}
+	}))
+	defer srv.Close()
+	app, err := authz.NewApp(1234, keyPEM, authz.WithAppAPIBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: app.Transport(srv.Client().Transport)}
+	call := func() string {
+		t.Helper()
+		resp, err := client.Get(srv.URL + "/api/v3/orgs/test-org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var body struct{ Authorization string }
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return body.Authorization
+	}
+
+	if got := call(); got != "Bearer ghs_42" {
+		t.Errorf("before reinstall: got=%q", got)
+	}
+	mux.Lock()
+	installationID = "44"
+	mux.Unlock()
+	if got := call(); got != "Bearer ghs_44" {
+		t.Errorf("after reinstall: got=%q", got)
+	}
+	if got := call(); got != "Bearer ghs_44" {
+		t.Errorf("after reinstall again: got=%q", got)
+	}
+	if lookups != 2 {
+		t.Errorf("installation lookups: got=%d want=2", lookups)
+	}
+}
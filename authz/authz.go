@@ -2,19 +2,113 @@ package authz
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"net/http"
 	"strings"
+)
 
-	"golang.org/x/oauth2"
+// Authorization schemes that GitHub accepts.
+const (
+	SchemeBearer = "Bearer"
+	SchemeToken  = "token"
+	SchemeBasic  = "Basic"
 )
 
-const separator = "Bearer "
+var schemes = []string{SchemeBearer, SchemeToken, SchemeBasic}
+
+// AnonymousFingerprint is the fingerprint of requests without any credential.
+const AnonymousFingerprint = "anonymous"
+
+// Authorization is the credential in the authorization header of the incoming request.
+type Authorization struct {
+	// Scheme is one of SchemeBearer, SchemeToken or SchemeBasic regardless of the case in the header.
+	Scheme string
+	// Credentials are the rest of the header as sent.
+	Credentials string
+	// Token is the token of Bearer and token schemes, or the password of Basic scheme.
+	Token string
+}
+
+// ParseAuthorization parses the authorization header of any scheme that GitHub accepts. The scheme is case-insensitive.
+func ParseAuthorization(authzHeader string) (*Authorization, bool) {
+	scheme, credentials, found := strings.Cut(strings.TrimSpace(authzHeader), " ")
+	credentials = strings.TrimSpace(credentials)
+	if !found || credentials == "" {
+		return nil, false
+	}
+	for _, s := range schemes {
+		if !strings.EqualFold(scheme, s) {
+			continue
+		}
+		a := &Authorization{Scheme: s, Credentials: credentials, Token: credentials}
+		if s == SchemeBasic {
+			decoded, err := base64.StdEncoding.DecodeString(credentials)
+			if err != nil {
+				return nil, false
+			}
+			_, password, found := strings.Cut(string(decoded), ":")
+			if !found || password == "" {
+				return nil, false
+			}
+			a.Token = password
+		}
+		return a, true
+	}
+	return nil, false
+}
+
+// Header returns the authorization header to forward to GitHub.
+func (a *Authorization) Header() string {
+	return a.Scheme + " " + a.Credentials
+}
 
+// ProxiedHTTPClient returns a client that calls GitHub with the credential in the authorization header.
+//
+// It returns http.DefaultClient if the header has no credential.
 func ProxiedHTTPClient(ctx context.Context, authzHeader string) *http.Client {
-	_, token, found := strings.Cut(authzHeader, separator)
+	a, found := ParseAuthorization(authzHeader)
 	if !found {
 		return http.DefaultClient
 	}
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	return oauth2.NewClient(ctx, ts)
+	return &http.Client{Transport: &authorizationTransport{base: http.DefaultTransport, header: a.Header()}}
+}
+
+type authorizationTransport struct {
+	base   http.RoundTripper
+	header string
+}
+
+var _ http.RoundTripper = (*authorizationTransport)(nil)
+
+func (t *authorizationTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("authorization", t.header)
+	return t.base.RoundTrip(r)
+}
+
+// Fingerprint returns a stable identifier of the token in the authorization header that is safe to record.
+//
+// Bearer and token schemes are identified by the token, so they share the fingerprint of the same token.
+// Basic scheme is identified by the whole credentials because the users may share the password.
+func Fingerprint(authzHeader string) string {
+	a, found := ParseAuthorization(authzHeader)
+	if !found {
+		return AnonymousFingerprint
+	}
+	key := a.Token
+	if a.Scheme == SchemeBasic {
+		key = a.Header()
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+func extractToken(authzHeader string) (string, bool) {
+	a, found := ParseAuthorization(authzHeader)
+	if !found {
+		return "", false
+	}
+	return a.Token, true
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/aereal/github-graphql-proxy/authz"
@@ -19,7 +20,13 @@ func TestProxiedHTTPClient(t *testing.T) {
 	}
 	testCases := []testCase{
 		{"Bearer", "Bearer 0xdeadbeaf", "Bearer 0xdeadbeaf"},
-		{"basic", fmt.Sprintf("Basic %s", base64.URLEncoding.EncodeToString([]byte("admin:pass"))), ""},
+		{"lowercase bearer", "bearer 0xdeadbeaf", "Bearer 0xdeadbeaf"},
+		{"token", "token 0xdeadbeaf", "token 0xdeadbeaf"},
+		{"uppercase token", "TOKEN 0xdeadbeaf", "token 0xdeadbeaf"},
+		{"basic", fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte("admin:pass"))), fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte("admin:pass")))},
+		{"malformed basic", "Basic !!!", ""},
+		{"unknown scheme", "Digest username=admin", ""},
+		{"no credentials", "Bearer ", ""},
 		{"no header", "", ""},
 	}
 	for _, tc := range testCases {
@@ -40,7 +47,7 @@ func TestProxiedHTTPClient(t *testing.T) {
 				t.Fatal(err)
 			}
 			if gotHeader != tc.wantAuthzHeader {
-				t.Errorf("authorization header: got=%q want=%q", tc.wantAuthzHeader, gotHeader)
+				t.Errorf("authorization header: got=%q want=%q", gotHeader, tc.wantAuthzHeader)
 			}
 			if resp.StatusCode != http.StatusOK {
 				t.Errorf("[bug] response status code: got=%d", resp.StatusCode)
@@ -48,3 +55,33 @@ func TestProxiedHTTPClient(t *testing.T) {
 		})
 	}
 }
+
+func TestFingerprint(t *testing.T) {
+	type testCase struct {
+		name       string
+		authzA     string
+		authzB     string
+		wantEquals bool
+	}
+	testCases := []testCase{
+		{"same token", "Bearer 0xdeadbeaf", "Bearer 0xdeadbeaf", true},
+		{"different tokens", "Bearer 0xdeadbeaf", "Bearer 0xcafebabe", false},
+		{"case insensitive scheme", "Bearer 0xdeadbeaf", "bearer 0xdeadbeaf", true},
+		{"token scheme", "token 0xdeadbeaf", "Bearer 0xdeadbeaf", true},
+		{"basic and bearer", "Basic " + base64.StdEncoding.EncodeToString([]byte("admin:0xdeadbeaf")), "Bearer 0xdeadbeaf", false},
+		{"basic users sharing password", "Basic " + base64.StdEncoding.EncodeToString([]byte("a:p")), "Basic " + base64.StdEncoding.EncodeToString([]byte("b:p")), false},
+		{"same basic credentials", "Basic " + base64.StdEncoding.EncodeToString([]byte("a:p")), "basic " + base64.StdEncoding.EncodeToString([]byte("a:p")), true},
+		{"both anonymous", "", "Digest username=admin", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, b := authz.Fingerprint(tc.authzA), authz.Fingerprint(tc.authzB)
+			if (a == b) != tc.wantEquals {
+				t.Errorf("fingerprints: a=%q b=%q wantEquals=%v", a, b, tc.wantEquals)
+			}
+			if strings.Contains(a, "deadbeaf") {
+				t.Errorf("fingerprint contains the token: %q", a)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package authz
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Response headers of GitHub that describe the credential of the request.
+const (
+	HeaderOAuthScopes               = "X-OAuth-Scopes"
+	HeaderAcceptedOAuthScopes       = "X-Accepted-OAuth-Scopes"
+	HeaderAcceptedGitHubPermissions = "X-Accepted-GitHub-Permissions"
+	HeaderTokenExpiration           = "GitHub-Authentication-Token-Expiration"
+	HeaderGitHubSSO                 = "X-GitHub-SSO"
+)
+
+var tokenExpirationLayouts = []string{"2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"}
+
+// TokenType is the kind of the token that the proxy calls GitHub with.
+type TokenType string
+
+const (
+	TokenTypeUnknown                        TokenType = "UNKNOWN"
+	TokenTypePersonalAccessToken            TokenType = "PERSONAL_ACCESS_TOKEN"
+	TokenTypeFineGrainedPersonalAccessToken TokenType = "FINE_GRAINED_PERSONAL_ACCESS_TOKEN"
+	TokenTypeOAuth                          TokenType = "OAUTH"
+	TokenTypeUserToServer                   TokenType = "USER_TO_SERVER"
+	TokenTypeInstallation                   TokenType = "INSTALLATION"
+)
+
+var tokenTypePrefixes = []struct {
+	prefix    string
+	tokenType TokenType
+}{
+	{"github_pat_", TokenTypeFineGrainedPersonalAccessToken},
+	{"ghp_", TokenTypePersonalAccessToken},
+	{"gho_", TokenTypeOAuth},
+	{"ghu_", TokenTypeUserToServer},
+	{"ghs_", TokenTypeInstallation},
+}
+
+// Scoped reports whether the token is granted OAuth scopes rather than fine-grained permissions.
+// Tokens of unknown type such as the old 40 hex digits tokens are classic, so they are scoped.
+func (t TokenType) Scoped() bool {
+	switch t {
+	case TokenTypeFineGrainedPersonalAccessToken, TokenTypeUserToServer, TokenTypeInstallation:
+		return false
+	default:
+		return true
+	}
+}
+
+// TokenTypeOf tells the kind of the token in the authorization header from its prefix.
+func TokenTypeOf(authzHeader string) TokenType {
+	token, found := extractToken(authzHeader)
+	if !found {
+		return TokenTypeUnknown
+	}
+	for _, p := range tokenTypePrefixes {
+		if strings.HasPrefix(token, p.prefix) {
+			return p.tokenType
+		}
+	}
+	return TokenTypeUnknown
+}
+
+type tokenTypeCtxKey struct{}
+
+// WithTokenType returns a new context that tells the kind of the token the proxy calls GitHub with.
+func WithTokenType(ctx context.Context, tokenType TokenType) context.Context {
+	return context.WithValue(ctx, tokenTypeCtxKey{}, tokenType)
+}
+
+// TokenTypeFromContext returns the kind of the token bound to the context, or TokenTypeUnknown.
+func TokenTypeFromContext(ctx context.Context) TokenType {
+	if tokenType, ok := ctx.Value(tokenTypeCtxKey{}).(TokenType); ok {
+		return tokenType
+	}
+	return TokenTypeUnknown
+}
+
+// Credential is what GitHub tells about the token in the response headers.
+type Credential struct {
+	// GrantedScopes are the OAuth scopes of the token. Fine-grained tokens have none.
+	GrantedScopes []string
+	// AcceptedScopes are the OAuth scopes that the endpoint accepts.
+	AcceptedScopes []string
+	// AcceptedPermissions are the fine-grained permissions that the endpoint accepts such as contents=read.
+	AcceptedPermissions []string
+	// ExpiresAt is nil if the token never expires.
+	ExpiresAt *time.Time
+	// SSOAuthorizationURL is where the token is authorized for the organization that enforces SAML SSO and rejected the request.
+	SSOAuthorizationURL string
+	// SSOUnauthorizedOrganizationIDs are the organizations that GitHub omitted from the results
+	// because they enforce SAML SSO and have not authorized the token.
+	SSOUnauthorizedOrganizationIDs []int64
+}
+
+// ParseCredential reads the credential from the response headers.
+func ParseCredential(header http.Header) *Credential {
+	c := &Credential{
+		GrantedScopes:       splitHeader(header.Get(HeaderOAuthScopes), ","),
+		AcceptedScopes:      splitHeader(header.Get(HeaderAcceptedOAuthScopes), ","),
+		AcceptedPermissions: splitHeader(header.Get(HeaderAcceptedGitHubPermissions), ";,"),
+	}
+	if v := header.Get(HeaderTokenExpiration); v != "" {
+		for _, layout := range tokenExpirationLayouts {
+			if expiresAt, err := time.Parse(layout, v); err == nil {
+				expiresAt = expiresAt.UTC()
+				c.ExpiresAt = &expiresAt
+				break
+			}
+		}
+	}
+	c.SSOAuthorizationURL, c.SSOUnauthorizedOrganizationIDs = parseSSO(header.Get(HeaderGitHubSSO))
+	return c
+}
+
+// parseSSO reads X-GitHub-SSO such as "required; url=https://github.com/orgs/octo-org/sso?authorization_request=..."
+// or "partial-results; organizations=21955855,20582480".
+func parseSSO(v string) (string, []int64) {
+	directive, params, _ := strings.Cut(v, ";")
+	var (
+		authorizationURL string
+		orgIDs           []int64
+	)
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch {
+		case directive == "required" && key == "url":
+			authorizationURL = value
+		case directive == "partial-results" && key == "organizations":
+			for _, x := range splitHeader(value, ",") {
+				if id, err := strconv.ParseInt(x, 10, 64); err == nil {
+					orgIDs = append(orgIDs, id)
+				}
+			}
+		}
+	}
+	return authorizationURL, orgIDs
+}
+
+// MissingScopes reports whether the endpoint accepts none of the granted scopes.
+func (c *Credential) MissingScopes() bool {
+	if len(c.AcceptedScopes) == 0 {
+		return false
+	}
+	for _, accepted := range c.AcceptedScopes {
+		for _, granted := range c.GrantedScopes {
+			if accepted == granted {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func splitHeader(v, seps string) []string {
+	var xs []string
+	for _, x := range strings.FieldsFunc(v, func(r rune) bool { return strings.ContainsRune(seps, r) }) {
+		if x = strings.TrimSpace(x); x != "" {
+			xs = append(xs, x)
+		}
+	}
+	return xs
+}
@@ -0,0 +1,109 @@
+package authz_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokenTypeOf(t *testing.T) {
+	type testCase struct {
+		authzHeader string
+		want        authz.TokenType
+		wantScoped  bool
+	}
+	testCases := []testCase{
+		{"Bearer ghp_0123456789", authz.TokenTypePersonalAccessToken, true},
+		{"Bearer github_pat_0123456789", authz.TokenTypeFineGrainedPersonalAccessToken, false},
+		{"Bearer gho_0123456789", authz.TokenTypeOAuth, true},
+		{"Bearer ghu_0123456789", authz.TokenTypeUserToServer, false},
+		{"Bearer ghs_0123456789", authz.TokenTypeInstallation, false},
+		{"Bearer 0xdeadbeaf", authz.TokenTypeUnknown, true},
+		{"", authz.TokenTypeUnknown, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.authzHeader, func(t *testing.T) {
+			got := authz.TokenTypeOf(tc.authzHeader)
+			if got != tc.want {
+				t.Errorf("got=%s want=%s", got, tc.want)
+			}
+			if scoped := got.Scoped(); scoped != tc.wantScoped {
+				t.Errorf("scoped: got=%v want=%v", scoped, tc.wantScoped)
+			}
+		})
+	}
+}
+
+func TestParseCredential(t *testing.T) {
+	expiresAt := time.Date(2023, time.March, 9, 15, 58, 41, 0, time.UTC)
+	type testCase struct {
+		name             string
+		header           http.Header
+		want             *authz.Credential
+		wantMissingScope bool
+	}
+	testCases := []testCase{
+		{
+			"granted",
+			http.Header{"X-Oauth-Scopes": {"repo, read:org"}, "X-Accepted-Oauth-Scopes": {"admin:org, read:org"}},
+			&authz.Credential{GrantedScopes: []string{"repo", "read:org"}, AcceptedScopes: []string{"admin:org", "read:org"}},
+			false,
+		},
+		{
+			"missing scopes",
+			http.Header{"X-Oauth-Scopes": {"repo"}, "X-Accepted-Oauth-Scopes": {"admin:org, read:org"}},
+			&authz.Credential{GrantedScopes: []string{"repo"}, AcceptedScopes: []string{"admin:org", "read:org"}},
+			true,
+		},
+		{
+			"no scopes required",
+			http.Header{"X-Oauth-Scopes": {""}, "X-Accepted-Oauth-Scopes": {""}},
+			&authz.Credential{},
+			false,
+		},
+		{
+			"fine-grained permissions",
+			http.Header{"X-Accepted-Github-Permissions": {"administration=read; organization_administration=read"}},
+			&authz.Credential{AcceptedPermissions: []string{"administration=read", "organization_administration=read"}},
+			false,
+		},
+		{
+			"expiration in UTC",
+			http.Header{"Github-Authentication-Token-Expiration": {"2023-03-09 15:58:41 UTC"}},
+			&authz.Credential{ExpiresAt: &expiresAt},
+			false,
+		},
+		{
+			"expiration with offset",
+			http.Header{"Github-Authentication-Token-Expiration": {"2023-03-09 10:58:41 -0500"}},
+			&authz.Credential{ExpiresAt: &expiresAt},
+			false,
+		},
+		{
+			"SSO required",
+			http.Header{"X-Github-Sso": {"required; url=https://github.com/orgs/test-org/sso?authorization_request=abc"}},
+			&authz.Credential{SSOAuthorizationURL: "https://github.com/orgs/test-org/sso?authorization_request=abc"},
+			false,
+		},
+		{
+			"SSO partial results",
+			http.Header{"X-Github-Sso": {"partial-results; organizations=21955855,20582480"}},
+			&authz.Credential{SSOUnauthorizedOrganizationIDs: []int64{21955855, 20582480}},
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := authz.ParseCredential(tc.header)
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("credential (-got, +want):\n%s", diff)
+			}
+			if gotMissing := got.MissingScopes(); gotMissing != tc.wantMissingScope {
+				t.Errorf("missing scopes: got=%v want=%v", gotMissing, tc.wantMissingScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package authz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/go-github/v47/github"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrInvalidPolicy = errors.New("invalid policy")
+	ErrPolicyDenied  = errors.New("denied by policy")
+)
+
+// Names of the rules reported in PolicyError.
+const (
+	PolicyRuleOrganizations = "organizations"
+	PolicyRuleBilling       = "billing"
+	PolicyRuleArtifacts     = "artifacts"
+)
+
+// Policy restricts the organizations and the repositories that the proxy serves.
+//
+// The zero value allows everything, and so does the nil.
+type Policy struct {
+	// Organizations lists the organizations the proxy serves, including the owners of the repositories.
+	// Empty allows any organization.
+	Organizations []string        `yaml:"organizations"`
+	Billing       BillingPolicy   `yaml:"billing"`
+	Artifacts     ArtifactsPolicy `yaml:"artifacts"`
+}
+
+type BillingPolicy struct {
+	// Teams lists the teams such as my-org/finance whose members can read the billing of the organization of the team.
+	// Empty allows anyone.
+	Teams []string `yaml:"teams"`
+}
+
+type ArtifactsPolicy struct {
+	// DeniedRepositories lists the repositories such as my-org/secret or my-org/* whose artifacts cannot be read.
+	DeniedRepositories []string `yaml:"deniedRepositories"`
+}
+
+// PolicyError tells which rule of the policy denied the access to the resource.
+type PolicyError struct {
+	Rule     string
+	Resource string
+	Reason   string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s: %s of %s: %s", ErrPolicyDenied, e.Rule, e.Resource, e.Reason)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrPolicyDenied
+}
+
+// LoadPolicyFile reads the policy from the YAML file at path. Unknown keys are rejected.
+func LoadPolicyFile(path string) (*Policy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	p := &Policy{}
+	if err := dec.Decode(p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %s", ErrInvalidPolicy, path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// Validate reports the malformed teams and repositories in the policy.
+func (p *Policy) Validate() error {
+	var errs []error
+	for _, team := range p.Billing.Teams {
+		if _, _, ok := splitPair(team); !ok {
+			errs = append(errs, fmt.Errorf("%w: billing.teams: must be in the form of org/team: %q", ErrInvalidPolicy, team))
+		}
+	}
+	for _, repo := range p.Artifacts.DeniedRepositories {
+		if _, _, ok := splitPair(repo); !ok {
+			errs = append(errs, fmt.Errorf("%w: artifacts.deniedRepositories: must be in the form of owner/name or owner/*: %q", ErrInvalidPolicy, repo))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AuthorizeOrganization returns PolicyError if the proxy does not serve the organization.
+func (p *Policy) AuthorizeOrganization(org string) error {
+	if p == nil || len(p.Organizations) == 0 {
+		return nil
+	}
+	for _, allowed := range p.Organizations {
+		if strings.EqualFold(allowed, org) {
+			return nil
+		}
+	}
+	return &PolicyError{Rule: PolicyRuleOrganizations, Resource: org, Reason: "the organization is not served"}
+}
+
+// AuthorizeBilling returns PolicyError unless the owner of the token is a member of the billing teams of the organization.
+//
+// It asks GitHub for the memberships with the client, so it must be the client of the token.
+func (p *Policy) AuthorizeBilling(ctx context.Context, client *github.Client, org string) error {
+	if err := p.AuthorizeOrganization(org); err != nil {
+		return err
+	}
+	if p == nil || len(p.Billing.Teams) == 0 {
+		return nil
+	}
+	var slugs []string
+	for _, team := range p.Billing.Teams {
+		if teamOrg, slug, _ := splitPair(team); strings.EqualFold(teamOrg, org) {
+			slugs = append(slugs, slug)
+		}
+	}
+	denied := &PolicyError{Rule: PolicyRuleBilling, Resource: org, Reason: "the token does not belong to any member of the billing teams"}
+	if len(slugs) == 0 {
+		return denied
+	}
+	if TokenTypeFromContext(ctx) == TokenTypeInstallation {
+		denied.Reason = "installation tokens do not belong to any member of the billing teams"
+		return denied
+	}
+	user, _, err := client.Users.Get(ctx, "")
+	if err != nil {
+		return fmt.Errorf("Users.Get: %w", err)
+	}
+	for _, slug := range slugs {
+		membership, _, err := client.Teams.GetTeamMembershipBySlug(ctx, org, slug, user.GetLogin())
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Teams.GetTeamMembershipBySlug: %w", err)
+		}
+		if membership.GetState() == "active" {
+			return nil
+		}
+	}
+	return denied
+}
+
+// AuthorizeArtifacts returns PolicyError if the artifacts of the repository are denied.
+func (p *Policy) AuthorizeArtifacts(owner, name string) error {
+	if err := p.AuthorizeOrganization(owner); err != nil {
+		return err
+	}
+	if p == nil {
+		return nil
+	}
+	for _, repo := range p.Artifacts.DeniedRepositories {
+		deniedOwner, deniedName, _ := splitPair(repo)
+		if strings.EqualFold(deniedOwner, owner) && (deniedName == "*" || strings.EqualFold(deniedName, name)) {
+			return &PolicyError{Rule: PolicyRuleArtifacts, Resource: owner + "/" + name, Reason: "the artifacts of the repository are denied"}
+		}
+	}
+	return nil
+}
+
+func splitPair(s string) (string, string, bool) {
+	left, right, found := strings.Cut(s, "/")
+	return left, right, found && left != "" && right != "" && !strings.Contains(right, "/")
+}
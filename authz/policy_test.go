@@ -0,0 +1,91 @@
+package authz_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadPolicyFile(t *testing.T) {
+	type testCase struct {
+		name    string
+		content string
+		want    *authz.Policy
+		wantErr error
+	}
+	testCases := []testCase{
+		{
+			"ok",
+			"organizations: [test-org]\nbilling:\n  teams: [test-org/finance]\nartifacts:\n  deniedRepositories: [test-org/secret, other-org/*]\n",
+			&authz.Policy{
+				Organizations: []string{"test-org"},
+				Billing:       authz.BillingPolicy{Teams: []string{"test-org/finance"}},
+				Artifacts:     authz.ArtifactsPolicy{DeniedRepositories: []string{"test-org/secret", "other-org/*"}},
+			},
+			nil,
+		},
+		{"empty", "", &authz.Policy{}, nil},
+		{"unknown key", "repositories: [test-org/test-repo]\n", nil, authz.ErrInvalidPolicy},
+		{"malformed team", "billing:\n  teams: [finance]\n", nil, authz.ErrInvalidPolicy},
+		{"malformed repository", "artifacts:\n  deniedRepositories: [test-org/a/b]\n", nil, authz.ErrInvalidPolicy},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := authz.LoadPolicyFile(path)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: got=%v want=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("policy (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPolicy_AuthorizeArtifacts(t *testing.T) {
+	policy := &authz.Policy{
+		Organizations: []string{"test-org", "other-org"},
+		Artifacts:     authz.ArtifactsPolicy{DeniedRepositories: []string{"test-org/secret", "other-org/*"}},
+	}
+	type testCase struct {
+		name     string
+		policy   *authz.Policy
+		owner    string
+		repo     string
+		wantRule string
+	}
+	testCases := []testCase{
+		{"allowed", policy, "test-org", "test-repo", ""},
+		{"denied repository", policy, "test-org", "secret", authz.PolicyRuleArtifacts},
+		{"case insensitive", policy, "Test-Org", "Secret", authz.PolicyRuleArtifacts},
+		{"denied owner", policy, "other-org", "test-repo", authz.PolicyRuleArtifacts},
+		{"not served organization", policy, "unknown-org", "test-repo", authz.PolicyRuleOrganizations},
+		{"nil policy", nil, "unknown-org", "secret", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.AuthorizeArtifacts(tc.owner, tc.repo)
+			var gotRule string
+			var policyErr *authz.PolicyError
+			if errors.As(err, &policyErr) {
+				gotRule = policyErr.Rule
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if gotRule != tc.wantRule {
+				t.Errorf("rule: got=%q want=%q", gotRule, tc.wantRule)
+			}
+			if err != nil && !errors.Is(err, authz.ErrPolicyDenied) {
+				t.Errorf("error must wrap ErrPolicyDenied: %v", err)
+			}
+		})
+	}
+}
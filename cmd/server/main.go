@@ -4,27 +4,133 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
-	"time"
+	"strings"
 
 	"github.com/aereal/github-graphql-proxy/server"
+	"github.com/aereal/github-graphql-proxy/tracing"
 )
 
 var (
-	addr         string
-	startTimeout time.Duration
+	cfg         = server.DefaultConfig()
+	configPath  string
+	printConfig bool
 )
 
 func init() {
-	flag.StringVar(&addr, "addr", ":8080", "server listening address")
-	flag.DurationVar(&startTimeout, "start-timeout", time.Second*5, "timeout to wait server spin-up")
+	flag.StringVar(&configPath, "config", os.Getenv("GITHUB_GRAPHQL_PROXY_CONFIG"), "path to the YAML config file; defaults to $GITHUB_GRAPHQL_PROXY_CONFIG")
+	flag.BoolVar(&printConfig, "print-config", false, "print the effective config and exit")
+	flag.StringVar(&cfg.Addr, "addr", cfg.Addr, "server listening address")
+	flag.StringVar(&cfg.Mode, "mode", cfg.Mode, "development or production; production disables the playground and introspection, and allows _service and /metrics only for trusted callers")
+	flag.Var(stringsFlag{&cfg.Service.TrustedCallers}, "service-trusted-callers", "comma separated IP addresses or CIDRs allowed to query _service in production mode")
+	flag.StringVar(&cfg.Service.Secret, "service-secret", cfg.Service.Secret, "shared secret in the "+server.ServiceSecretHeader+" header that allows _service in production mode")
+	flag.BoolVar(&cfg.Auth.Strict, "strict-auth", cfg.Auth.Strict, "reject GraphQL requests without a GitHub token with 401 instead of calling GitHub anonymously")
+	flag.BoolVar(&cfg.Auth.AllowApp, "strict-auth-allow-app", cfg.Auth.AllowApp, "let strict auth accept requests without a GitHub token that the GitHub App authenticates in fallback mode")
+	flag.StringVar(&cfg.Policy.Path, "policy", cfg.Policy.Path, "path to the YAML file of the access policy of the organizations and the repositories")
+	flag.DurationVar(&cfg.StartTimeout, "start-timeout", cfg.StartTimeout, "timeout to wait server spin-up")
+	flag.BoolVar(&cfg.Playground, "playground", cfg.Playground, "serve the GraphQL playground at /")
+	flag.BoolVar(&cfg.Introspection, "introspection", cfg.Introspection, "allow introspection queries")
+	flag.IntVar(&cfg.Cache.Size, "cache-size", cfg.Cache.Size, "max number of upstream responses to cache; 0 disables caching")
+	flag.DurationVar(&cfg.Cache.TTL, "cache-ttl", cfg.Cache.TTL, "duration to serve cached upstream responses without revalidation")
+	flag.IntVar(&cfg.APQ.CacheSize, "apq-cache-size", cfg.APQ.CacheSize, "max number of queries kept for automatic persisted queries; 0 disables them")
+	flag.IntVar(&cfg.Limits.MaxComplexity, "max-complexity", cfg.Limits.MaxComplexity, "max complexity of an operation; 0 disables the limit")
+	flag.IntVar(&cfg.Limits.MaxDepth, "max-depth", cfg.Limits.MaxDepth, "max depth of an operation; 0 disables the limit")
+	flag.IntVar(&cfg.Retry.MaxAttempts, "retry-max-attempts", cfg.Retry.MaxAttempts, "max attempts of upstream calls; 1 disables retries")
+	flag.DurationVar(&cfg.Retry.MaxWait, "retry-max-wait", cfg.Retry.MaxWait, "max duration to wait before retrying an upstream call")
+	flag.Int64Var(&cfg.Concurrency.Max, "max-concurrency", cfg.Concurrency.Max, "max number of concurrent upstream calls across the process; 0 means unlimited")
+	flag.Int64Var(&cfg.Concurrency.PerToken, "max-concurrency-per-token", cfg.Concurrency.PerToken, "max number of concurrent upstream calls for each token; 0 means unlimited")
+	flag.Int64Var(&cfg.Concurrency.Resolvers, "max-resolver-concurrency", cfg.Concurrency.Resolvers, "max number of resolvers running concurrently in an operation; 0 means unlimited")
+	flag.StringVar(&cfg.GitHub.APIURL, "github-api-url", cfg.GitHub.APIURL, "base URL of GitHub Enterprise Server API such as https://ghes.example.com/api/v3/; defaults to GitHub.com")
+	flag.StringVar(&cfg.GitHub.UploadURL, "github-upload-url", cfg.GitHub.UploadURL, "upload URL of GitHub Enterprise Server; defaults to derived from -github-api-url")
+	flag.StringVar(&cfg.Tracing.Exporter, "trace-exporter", cfg.Tracing.Exporter, "OpenTelemetry trace exporter: none, stdout or otlp")
+	flag.StringVar(&cfg.Tracing.OTLPEndpoint, "trace-otlp-endpoint", cfg.Tracing.OTLPEndpoint, "URL of the OTLP/HTTP collector such as http://localhost:4318; defaults to OTEL_EXPORTER_OTLP_ENDPOINT")
+	flag.StringVar(&cfg.Log.Format, "log-format", cfg.Log.Format, "log format: json or text")
+	flag.StringVar(&cfg.Log.Level, "log-level", cfg.Log.Level, "minimum log level: debug, info, warn or error")
+	flag.DurationVar(&cfg.DrainDelay, "drain-delay", cfg.DrainDelay, "duration to keep serving after /readyz starts failing on shutdown")
+	flag.StringVar(&cfg.GitHub.ReadinessCheckToken, "readiness-check-token", cfg.GitHub.ReadinessCheckToken, "if set, /readyz checks GitHub is reachable with the token")
+	flag.Int64Var(&cfg.GitHub.App.ID, "github-app-id", cfg.GitHub.App.ID, "GitHub App ID to authenticate as its installations")
+	flag.StringVar(&cfg.GitHub.App.PrivateKeyPath, "github-app-private-key", cfg.GitHub.App.PrivateKeyPath, "path to the PEM encoded private key of the GitHub App")
+	flag.StringVar(&cfg.GitHub.App.AuthMode, "github-app-auth-mode", cfg.GitHub.App.AuthMode, "when to use GitHub App installation tokens: fallback (requests without a token) or always")
+}
+
+// stringsFlag is flag.Value of comma separated strings.
+type stringsFlag struct {
+	p *[]string
+}
+
+func (f stringsFlag) String() string {
+	if f.p == nil {
+		return ""
+	}
+	return strings.Join(*f.p, ",")
+}
+
+func (f stringsFlag) Set(s string) error {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*f.p = items
+	return nil
 }
 
 func main() {
 	flag.Parse()
 	ctx := context.Background()
-	if err := server.Start(ctx, addr, startTimeout); err != nil {
+	if err := run(ctx); err != nil {
 		fmt.Fprintf(os.Stderr, "%+v\n", err)
 		os.Exit(1)
 	}
 }
+
+// loadConfig resolves the config in the order of precedence: flags, environment variables, the config file and defaults.
+func loadConfig() error {
+	explicit := map[string]string{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = f.Value.String() })
+	if configPath != "" {
+		if err := server.LoadConfigFile(configPath, &cfg); err != nil {
+			return err
+		}
+	}
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		return err
+	}
+	for name, value := range explicit {
+		if err := flag.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return cfg.Validate()
+}
+
+func run(ctx context.Context) error {
+	if err := loadConfig(); err != nil {
+		return err
+	}
+	if printConfig {
+		return cfg.Write(os.Stdout)
+	}
+	logger, err := server.NewLogger(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{Exporter: cfg.Tracing.Exporter, Endpoint: cfg.Tracing.OTLPEndpoint})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to shutdown tracer provider: %+v\n", err)
+		}
+	}()
+	opts, err := cfg.Options()
+	if err != nil {
+		return err
+	}
+	opts = append(opts, server.WithLogger(logger))
+	return server.Start(ctx, cfg.Addr, cfg.StartTimeout, opts...)
+}
@@ -0,0 +1,51 @@
+package githubgraphqlproxy
+
+const (
+	// RESTCallComplexity is the complexity of a field whose resolver calls GitHub REST API.
+	RESTCallComplexity = 10
+
+	// DefaultArtifactsPageSize is the number of artifacts returned when neither first nor last is given.
+	DefaultArtifactsPageSize = 30
+
+	// ArtifactsSizePages is the number of the pages of 100 artifacts that totalSizeInBytes is charged for.
+	// It fetches all the pages however many they are, but the number is unknown until the first page is fetched.
+	ArtifactsSizePages = 10
+)
+
+// NewComplexityRoot returns the complexity functions that weigh the fields by the REST calls they make
+// and multiply the connection fields by the number of requested items.
+//
+// The childComplexity of a connection field should be the complexity of its edges and nodes only,
+// so that the other fields such as totalCount and pageInfo are not multiplied.
+// The server computes the complexity that way.
+func NewComplexityRoot() ComplexityRoot {
+	var c ComplexityRoot
+	restCall := func(childComplexity int) int {
+		return RESTCallComplexity + childComplexity
+	}
+	c.Organization.Plan = restCall
+	c.OrganizationBilling.Actions = restCall
+	c.OrganizationBilling.Storage = restCall
+	c.Query.ViewerCredential = restCall
+	c.ViewerCredential.SsoAuthorization = restCall
+	c.Repository.Artifacts = func(childComplexity int, first *int, after *string, last *int, before *string, page *int) int {
+		count := DefaultArtifactsPageSize
+		switch {
+		case first != nil:
+			count = *first
+		case last != nil:
+			count = *last
+		}
+		if count < 1 {
+			count = 1
+		}
+		return RESTCallComplexity + count*childComplexity
+	}
+	c.RepositoryArtifactConnection.TotalSizeInBytes = func(childComplexity int) int {
+		return ArtifactsSizePages * RESTCallComplexity
+	}
+	c.Query.__resolve_entities = func(childComplexity int, representations []map[string]interface{}) int {
+		return len(representations) * childComplexity
+	}
+	return c
+}
@@ -1,5 +1,11 @@
 package githubgraphqlproxy
 
+import (
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+)
+
 type Organization struct {
 	Login   string `json:"login"`
 	Billing *OrganizationBilling
@@ -19,8 +25,22 @@ type Repository struct {
 
 func (Repository) IsEntity() {}
 
+type ViewerCredential struct {
+	TokenType authz.TokenType `json:"tokenType"`
+	Scopes    []string        `json:"scopes"`
+	ExpiresAt *time.Time      `json:"expiresAt"`
+}
+
+type SSOAuthorization struct {
+	AuthorizedOrganizations     []string `json:"authorizedOrganizations"`
+	UnauthorizedOrganizationIDs []int64  `json:"unauthorizedOrganizationIds"`
+}
+
 type RepositoryArtifactConnection struct {
-	TotalCount       int         `json:"totalCount"`
-	TotalSizeInBytes int64       `json:"totalSizeInBytes"`
-	Nodes            []*Artifact `json:"nodes"`
+	Owner      string                    `json:"-"`
+	Name       string                    `json:"-"`
+	TotalCount int                       `json:"totalCount"`
+	Edges      []*RepositoryArtifactEdge `json:"edges"`
+	Nodes      []*Artifact               `json:"nodes"`
+	PageInfo   *PageInfo                 `json:"pageInfo"`
 }
@@ -42,6 +42,17 @@ type Artifact struct {
 	ExpiresAt          time.Time `json:"expiresAt"`
 }
 
+type OrganizationByLoginsInput struct {
+	Login string `json:"Login"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
 type Plan struct {
 	Name          *string `json:"name"`
 	Space         *int    `json:"space"`
@@ -51,6 +62,15 @@ type Plan struct {
 	Seats         *int    `json:"seats"`
 }
 
+type RepositoryArtifactEdge struct {
+	Cursor string    `json:"cursor"`
+	Node   *Artifact `json:"node"`
+}
+
+type RepositoryByNameWithOwnersInput struct {
+	NameWithOwner string `json:"NameWithOwner"`
+}
+
 type StorageBilling struct {
 	DaysLeftInBillingCycle       int     `json:"daysLeftInBillingCycle"`
 	EstimatedPaidStorageForMonth float64 `json:"estimatedPaidStorageForMonth"`
@@ -16,6 +16,7 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/introspection"
 	"github.com/99designs/gqlgen/plugin/federation/fedruntime"
+	"github.com/aereal/github-graphql-proxy/authz"
 	gqlparser "github.com/vektah/gqlparser/v2"
 	"github.com/vektah/gqlparser/v2/ast"
 )
@@ -43,6 +44,8 @@ type ResolverRoot interface {
 	OrganizationBilling() OrganizationBillingResolver
 	Query() QueryResolver
 	Repository() RepositoryResolver
+	RepositoryArtifactConnection() RepositoryArtifactConnectionResolver
+	ViewerCredential() ViewerCredentialResolver
 }
 
 type DirectiveRoot struct {
@@ -86,8 +89,8 @@ type ComplexityRoot struct {
 	}
 
 	Entity struct {
-		FindOrganizationByLogin       func(childComplexity int, login string) int
-		FindRepositoryByNameWithOwner func(childComplexity int, nameWithOwner string) int
+		FindManyOrganizationByLogins       func(childComplexity int, reps []*OrganizationByLoginsInput) int
+		FindManyRepositoryByNameWithOwners func(childComplexity int, reps []*RepositoryByNameWithOwnersInput) int
 	}
 
 	Organization struct {
@@ -101,6 +104,13 @@ type ComplexityRoot struct {
 		Storage func(childComplexity int) int
 	}
 
+	PageInfo struct {
+		EndCursor       func(childComplexity int) int
+		HasNextPage     func(childComplexity int) int
+		HasPreviousPage func(childComplexity int) int
+		StartCursor     func(childComplexity int) int
+	}
+
 	Plan struct {
 		Collaborators func(childComplexity int) int
 		FilledSeats   func(childComplexity int) int
@@ -113,35 +123,55 @@ type ComplexityRoot struct {
 	Query struct {
 		TestOrganization   func(childComplexity int, login string) int
 		TestRepository     func(childComplexity int, owner string, name string) int
+		ViewerCredential   func(childComplexity int) int
 		__resolve__service func(childComplexity int) int
 		__resolve_entities func(childComplexity int, representations []map[string]interface{}) int
 	}
 
 	Repository struct {
-		Artifacts     func(childComplexity int, first *int, page *int) int
+		Artifacts     func(childComplexity int, first *int, after *string, last *int, before *string, page *int) int
 		NameWithOwner func(childComplexity int) int
 	}
 
 	RepositoryArtifactConnection struct {
+		Edges            func(childComplexity int) int
 		Nodes            func(childComplexity int) int
+		PageInfo         func(childComplexity int) int
 		TotalCount       func(childComplexity int) int
 		TotalSizeInBytes func(childComplexity int) int
 	}
 
+	RepositoryArtifactEdge struct {
+		Cursor func(childComplexity int) int
+		Node   func(childComplexity int) int
+	}
+
+	SSOAuthorization struct {
+		AuthorizedOrganizations     func(childComplexity int) int
+		UnauthorizedOrganizationIDs func(childComplexity int) int
+	}
+
 	StorageBilling struct {
 		DaysLeftInBillingCycle       func(childComplexity int) int
 		EstimatedPaidStorageForMonth func(childComplexity int) int
 		EstimatedStorageForMonth     func(childComplexity int) int
 	}
 
+	ViewerCredential struct {
+		ExpiresAt        func(childComplexity int) int
+		Scopes           func(childComplexity int) int
+		SsoAuthorization func(childComplexity int) int
+		TokenType        func(childComplexity int) int
+	}
+
 	_Service struct {
 		SDL func(childComplexity int) int
 	}
 }
 
 type EntityResolver interface {
-	FindOrganizationByLogin(ctx context.Context, login string) (*Organization, error)
-	FindRepositoryByNameWithOwner(ctx context.Context, nameWithOwner string) (*Repository, error)
+	FindManyOrganizationByLogins(ctx context.Context, reps []*OrganizationByLoginsInput) ([]*Organization, error)
+	FindManyRepositoryByNameWithOwners(ctx context.Context, reps []*RepositoryByNameWithOwnersInput) ([]*Repository, error)
 }
 type OrganizationResolver interface {
 	Plan(ctx context.Context, obj *Organization) (*Plan, error)
@@ -153,9 +183,16 @@ type OrganizationBillingResolver interface {
 type QueryResolver interface {
 	TestOrganization(ctx context.Context, login string) (*Organization, error)
 	TestRepository(ctx context.Context, owner string, name string) (*Repository, error)
+	ViewerCredential(ctx context.Context) (*ViewerCredential, error)
 }
 type RepositoryResolver interface {
-	Artifacts(ctx context.Context, obj *Repository, first *int, page *int) (*RepositoryArtifactConnection, error)
+	Artifacts(ctx context.Context, obj *Repository, first *int, after *string, last *int, before *string, page *int) (*RepositoryArtifactConnection, error)
+}
+type RepositoryArtifactConnectionResolver interface {
+	TotalSizeInBytes(ctx context.Context, obj *RepositoryArtifactConnection) (*int, error)
+}
+type ViewerCredentialResolver interface {
+	SsoAuthorization(ctx context.Context, obj *ViewerCredential) (*SSOAuthorization, error)
 }
 
 type executableSchema struct {
@@ -299,29 +336,29 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Artifact.SizeInBytes(childComplexity), true
 
-	case "Entity.findOrganizationByLogin":
-		if e.complexity.Entity.FindOrganizationByLogin == nil {
+	case "Entity.findManyOrganizationByLogins":
+		if e.complexity.Entity.FindManyOrganizationByLogins == nil {
 			break
 		}
 
-		args, err := ec.field_Entity_findOrganizationByLogin_args(context.TODO(), rawArgs)
+		args, err := ec.field_Entity_findManyOrganizationByLogins_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Entity.FindOrganizationByLogin(childComplexity, args["login"].(string)), true
+		return e.complexity.Entity.FindManyOrganizationByLogins(childComplexity, args["reps"].([]*OrganizationByLoginsInput)), true
 
-	case "Entity.findRepositoryByNameWithOwner":
-		if e.complexity.Entity.FindRepositoryByNameWithOwner == nil {
+	case "Entity.findManyRepositoryByNameWithOwners":
+		if e.complexity.Entity.FindManyRepositoryByNameWithOwners == nil {
 			break
 		}
 
-		args, err := ec.field_Entity_findRepositoryByNameWithOwner_args(context.TODO(), rawArgs)
+		args, err := ec.field_Entity_findManyRepositoryByNameWithOwners_args(context.TODO(), rawArgs)
 		if err != nil {
 			return 0, false
 		}
 
-		return e.complexity.Entity.FindRepositoryByNameWithOwner(childComplexity, args["nameWithOwner"].(string)), true
+		return e.complexity.Entity.FindManyRepositoryByNameWithOwners(childComplexity, args["reps"].([]*RepositoryByNameWithOwnersInput)), true
 
 	case "Organization.billing":
 		if e.complexity.Organization.Billing == nil {
@@ -358,6 +395,34 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.OrganizationBilling.Storage(childComplexity), true
 
+	case "PageInfo.endCursor":
+		if e.complexity.PageInfo.EndCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.EndCursor(childComplexity), true
+
+	case "PageInfo.hasNextPage":
+		if e.complexity.PageInfo.HasNextPage == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.HasNextPage(childComplexity), true
+
+	case "PageInfo.hasPreviousPage":
+		if e.complexity.PageInfo.HasPreviousPage == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.HasPreviousPage(childComplexity), true
+
+	case "PageInfo.startCursor":
+		if e.complexity.PageInfo.StartCursor == nil {
+			break
+		}
+
+		return e.complexity.PageInfo.StartCursor(childComplexity), true
+
 	case "Plan.collaborators":
 		if e.complexity.Plan.Collaborators == nil {
 			break
@@ -424,6 +489,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Query.TestRepository(childComplexity, args["owner"].(string), args["name"].(string)), true
 
+	case "Query.viewerCredential":
+		if e.complexity.Query.ViewerCredential == nil {
+			break
+		}
+
+		return e.complexity.Query.ViewerCredential(childComplexity), true
+
 	case "Query._service":
 		if e.complexity.Query.__resolve__service == nil {
 			break
@@ -453,7 +525,7 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 			return 0, false
 		}
 
-		return e.complexity.Repository.Artifacts(childComplexity, args["first"].(*int), args["page"].(*int)), true
+		return e.complexity.Repository.Artifacts(childComplexity, args["first"].(*int), args["after"].(*string), args["last"].(*int), args["before"].(*string), args["page"].(*int)), true
 
 	case "Repository.nameWithOwner":
 		if e.complexity.Repository.NameWithOwner == nil {
@@ -462,6 +534,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.Repository.NameWithOwner(childComplexity), true
 
+	case "RepositoryArtifactConnection.edges":
+		if e.complexity.RepositoryArtifactConnection.Edges == nil {
+			break
+		}
+
+		return e.complexity.RepositoryArtifactConnection.Edges(childComplexity), true
+
 	case "RepositoryArtifactConnection.nodes":
 		if e.complexity.RepositoryArtifactConnection.Nodes == nil {
 			break
@@ -469,6 +548,13 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RepositoryArtifactConnection.Nodes(childComplexity), true
 
+	case "RepositoryArtifactConnection.pageInfo":
+		if e.complexity.RepositoryArtifactConnection.PageInfo == nil {
+			break
+		}
+
+		return e.complexity.RepositoryArtifactConnection.PageInfo(childComplexity), true
+
 	case "RepositoryArtifactConnection.totalCount":
 		if e.complexity.RepositoryArtifactConnection.TotalCount == nil {
 			break
@@ -483,6 +569,34 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.RepositoryArtifactConnection.TotalSizeInBytes(childComplexity), true
 
+	case "RepositoryArtifactEdge.cursor":
+		if e.complexity.RepositoryArtifactEdge.Cursor == nil {
+			break
+		}
+
+		return e.complexity.RepositoryArtifactEdge.Cursor(childComplexity), true
+
+	case "RepositoryArtifactEdge.node":
+		if e.complexity.RepositoryArtifactEdge.Node == nil {
+			break
+		}
+
+		return e.complexity.RepositoryArtifactEdge.Node(childComplexity), true
+
+	case "SSOAuthorization.authorizedOrganizations":
+		if e.complexity.SSOAuthorization.AuthorizedOrganizations == nil {
+			break
+		}
+
+		return e.complexity.SSOAuthorization.AuthorizedOrganizations(childComplexity), true
+
+	case "SSOAuthorization.unauthorizedOrganizationIds":
+		if e.complexity.SSOAuthorization.UnauthorizedOrganizationIDs == nil {
+			break
+		}
+
+		return e.complexity.SSOAuthorization.UnauthorizedOrganizationIDs(childComplexity), true
+
 	case "StorageBilling.daysLeftInBillingCycle":
 		if e.complexity.StorageBilling.DaysLeftInBillingCycle == nil {
 			break
@@ -504,6 +618,34 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 
 		return e.complexity.StorageBilling.EstimatedStorageForMonth(childComplexity), true
 
+	case "ViewerCredential.expiresAt":
+		if e.complexity.ViewerCredential.ExpiresAt == nil {
+			break
+		}
+
+		return e.complexity.ViewerCredential.ExpiresAt(childComplexity), true
+
+	case "ViewerCredential.scopes":
+		if e.complexity.ViewerCredential.Scopes == nil {
+			break
+		}
+
+		return e.complexity.ViewerCredential.Scopes(childComplexity), true
+
+	case "ViewerCredential.ssoAuthorization":
+		if e.complexity.ViewerCredential.SsoAuthorization == nil {
+			break
+		}
+
+		return e.complexity.ViewerCredential.SsoAuthorization(childComplexity), true
+
+	case "ViewerCredential.tokenType":
+		if e.complexity.ViewerCredential.TokenType == nil {
+			break
+		}
+
+		return e.complexity.ViewerCredential.TokenType(childComplexity), true
+
 	case "_Service.sdl":
 		if e.complexity._Service.SDL == nil {
 			break
@@ -518,7 +660,10 @@ func (e *executableSchema) Complexity(typeName, field string, childComplexity in
 func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 	rc := graphql.GetOperationContext(ctx)
 	ec := executionContext{rc, e}
-	inputUnmarshalMap := graphql.BuildUnmarshalerMap()
+	inputUnmarshalMap := graphql.BuildUnmarshalerMap(
+		ec.unmarshalInputOrganizationByLoginsInput,
+		ec.unmarshalInputRepositoryByNameWithOwnersInput,
+	)
 	first := true
 
 	switch rc.Operation.Operation {
@@ -595,10 +740,18 @@ var sources = []*ast.Source{
 # a union of all types that use the @key directive
 union _Entity = Organization | Repository
 
+input OrganizationByLoginsInput {
+	Login: String!
+}
+
+input RepositoryByNameWithOwnersInput {
+	NameWithOwner: String!
+}
+
 # fake type to build resolver interfaces for users to implement
 type Entity {
-		findOrganizationByLogin(login: String!,): Organization!
-	findRepositoryByNameWithOwner(nameWithOwner: String!,): Repository!
+		findManyOrganizationByLogins(reps: [OrganizationByLoginsInput!]!): [Organization]
+	findManyRepositoryByNameWithOwners(reps: [RepositoryByNameWithOwnersInput!]!): [Repository]
 
 }
 
@@ -618,33 +771,33 @@ var parsedSchema = gqlparser.MustLoadSchema(sources...)
 
 // region    ***************************** args.gotpl *****************************
 
-func (ec *executionContext) field_Entity_findOrganizationByLogin_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Entity_findManyOrganizationByLogins_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["login"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("login"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+	var arg0 []*OrganizationByLoginsInput
+	if tmp, ok := rawArgs["reps"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("reps"))
+		arg0, err = ec.unmarshalNOrganizationByLoginsInput2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganizationByLoginsInputᚄ(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	args["login"] = arg0
+	args["reps"] = arg0
 	return args, nil
 }
 
-func (ec *executionContext) field_Entity_findRepositoryByNameWithOwner_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
+func (ec *executionContext) field_Entity_findManyRepositoryByNameWithOwners_args(ctx context.Context, rawArgs map[string]interface{}) (map[string]interface{}, error) {
 	var err error
 	args := map[string]interface{}{}
-	var arg0 string
-	if tmp, ok := rawArgs["nameWithOwner"]; ok {
-		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("nameWithOwner"))
-		arg0, err = ec.unmarshalNString2string(ctx, tmp)
+	var arg0 []*RepositoryByNameWithOwnersInput
+	if tmp, ok := rawArgs["reps"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("reps"))
+		arg0, err = ec.unmarshalNRepositoryByNameWithOwnersInput2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryByNameWithOwnersInputᚄ(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	args["nameWithOwner"] = arg0
+	args["reps"] = arg0
 	return args, nil
 }
 
@@ -729,15 +882,42 @@ func (ec *executionContext) field_Repository_artifacts_args(ctx context.Context,
 		}
 	}
 	args["first"] = arg0
-	var arg1 *int
+	var arg1 *string
+	if tmp, ok := rawArgs["after"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("after"))
+		arg1, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["after"] = arg1
+	var arg2 *int
+	if tmp, ok := rawArgs["last"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("last"))
+		arg2, err = ec.unmarshalOInt2ᚖint(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["last"] = arg2
+	var arg3 *string
+	if tmp, ok := rawArgs["before"]; ok {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("before"))
+		arg3, err = ec.unmarshalOString2ᚖstring(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	args["before"] = arg3
+	var arg4 *int
 	if tmp, ok := rawArgs["page"]; ok {
 		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("page"))
-		arg1, err = ec.unmarshalOInt2ᚖint(ctx, tmp)
+		arg4, err = ec.unmarshalOInt2ᚖint(ctx, tmp)
 		if err != nil {
 			return nil, err
 		}
 	}
-	args["page"] = arg1
+	args["page"] = arg4
 	return args, nil
 }
 
@@ -1572,8 +1752,8 @@ func (ec *executionContext) fieldContext_Artifact_expiresAt(ctx context.Context,
 	return fc, nil
 }
 
-func (ec *executionContext) _Entity_findOrganizationByLogin(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Entity_findOrganizationByLogin(ctx, field)
+func (ec *executionContext) _Entity_findManyOrganizationByLogins(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Entity_findManyOrganizationByLogins(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1586,24 +1766,21 @@ func (ec *executionContext) _Entity_findOrganizationByLogin(ctx context.Context,
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Entity().FindOrganizationByLogin(rctx, fc.Args["login"].(string))
+		return ec.resolvers.Entity().FindManyOrganizationByLogins(rctx, fc.Args["reps"].([]*OrganizationByLoginsInput))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*Organization)
+	res := resTmp.([]*Organization)
 	fc.Result = res
-	return ec.marshalNOrganization2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx, field.Selections, res)
+	return ec.marshalOOrganization2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Entity_findOrganizationByLogin(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Entity_findManyOrganizationByLogins(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Entity",
 		Field:      field,
@@ -1628,15 +1805,15 @@ func (ec *executionContext) fieldContext_Entity_findOrganizationByLogin(ctx cont
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Entity_findOrganizationByLogin_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Entity_findManyOrganizationByLogins_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _Entity_findRepositoryByNameWithOwner(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Entity_findRepositoryByNameWithOwner(ctx, field)
+func (ec *executionContext) _Entity_findManyRepositoryByNameWithOwners(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Entity_findManyRepositoryByNameWithOwners(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -1649,24 +1826,21 @@ func (ec *executionContext) _Entity_findRepositoryByNameWithOwner(ctx context.Co
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Entity().FindRepositoryByNameWithOwner(rctx, fc.Args["nameWithOwner"].(string))
+		return ec.resolvers.Entity().FindManyRepositoryByNameWithOwners(rctx, fc.Args["reps"].([]*RepositoryByNameWithOwnersInput))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(*Repository)
+	res := resTmp.([]*Repository)
 	fc.Result = res
-	return ec.marshalNRepository2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx, field.Selections, res)
+	return ec.marshalORepository2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_Entity_findRepositoryByNameWithOwner(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_Entity_findManyRepositoryByNameWithOwners(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Entity",
 		Field:      field,
@@ -1689,7 +1863,7 @@ func (ec *executionContext) fieldContext_Entity_findRepositoryByNameWithOwner(ct
 		}
 	}()
 	ctx = graphql.WithFieldContext(ctx, fc)
-	if fc.Args, err = ec.field_Entity_findRepositoryByNameWithOwner_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+	if fc.Args, err = ec.field_Entity_findManyRepositoryByNameWithOwners_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
 		ec.Error(ctx, err)
 		return
 	}
@@ -1951,6 +2125,176 @@ func (ec *executionContext) fieldContext_OrganizationBilling_storage(ctx context
 	return fc, nil
 }
 
+func (ec *executionContext) _PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_hasNextPage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasNextPage, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_hasNextPage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_hasPreviousPage(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.HasPreviousPage, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(bool)
+	fc.Result = res
+	return ec.marshalNBoolean2bool(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_hasPreviousPage(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_startCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_startCursor(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.StartCursor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_startCursor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _PageInfo_endCursor(ctx context.Context, field graphql.CollectedField, obj *PageInfo) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_PageInfo_endCursor(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.EndCursor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*string)
+	fc.Result = res
+	return ec.marshalOString2ᚖstring(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_PageInfo_endCursor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "PageInfo",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Plan_name(ctx context.Context, field graphql.CollectedField, obj *Plan) (ret graphql.Marshaler) {
 	fc, err := ec.fieldContext_Plan_name(ctx, field)
 	if err != nil {
@@ -2315,8 +2659,8 @@ func (ec *executionContext) fieldContext_Query_test__repository(ctx context.Cont
 	return fc, nil
 }
 
-func (ec *executionContext) _Query__entities(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_Query__entities(ctx, field)
+func (ec *executionContext) _Query_viewerCredential(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query_viewerCredential(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2329,7 +2673,7 @@ func (ec *executionContext) _Query__entities(ctx context.Context, field graphql.
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.__resolve_entities(ctx, fc.Args["representations"].([]map[string]interface{})), nil
+		return ec.resolvers.Query().ViewerCredential(rctx)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2341,9 +2685,63 @@ func (ec *executionContext) _Query__entities(ctx context.Context, field graphql.
 		}
 		return graphql.Null
 	}
-	res := resTmp.([]fedruntime.Entity)
+	res := resTmp.(*ViewerCredential)
 	fc.Result = res
-	return ec.marshalN_Entity2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋpluginᚋfederationᚋfedruntimeᚐEntity(ctx, field.Selections, res)
+	return ec.marshalNViewerCredential2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐViewerCredential(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_Query_viewerCredential(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Query",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "tokenType":
+				return ec.fieldContext_ViewerCredential_tokenType(ctx, field)
+			case "scopes":
+				return ec.fieldContext_ViewerCredential_scopes(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_ViewerCredential_expiresAt(ctx, field)
+			case "ssoAuthorization":
+				return ec.fieldContext_ViewerCredential_ssoAuthorization(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type ViewerCredential", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Query__entities(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_Query__entities(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return ec.__resolve_entities(ctx, fc.Args["representations"].([]map[string]interface{})), nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]fedruntime.Entity)
+	fc.Result = res
+	return ec.marshalN_Entity2ᚕgithubᚗcomᚋ99designsᚋgqlgenᚋpluginᚋfederationᚋfedruntimeᚐEntity(ctx, field.Selections, res)
 }
 
 func (ec *executionContext) fieldContext_Query__entities(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
@@ -2605,7 +3003,7 @@ func (ec *executionContext) _Repository_artifacts(ctx context.Context, field gra
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return ec.resolvers.Repository().Artifacts(rctx, obj, fc.Args["first"].(*int), fc.Args["page"].(*int))
+		return ec.resolvers.Repository().Artifacts(rctx, obj, fc.Args["first"].(*int), fc.Args["after"].(*string), fc.Args["last"].(*int), fc.Args["before"].(*string), fc.Args["page"].(*int))
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2634,8 +3032,12 @@ func (ec *executionContext) fieldContext_Repository_artifacts(ctx context.Contex
 				return ec.fieldContext_RepositoryArtifactConnection_totalCount(ctx, field)
 			case "totalSizeInBytes":
 				return ec.fieldContext_RepositoryArtifactConnection_totalSizeInBytes(ctx, field)
+			case "edges":
+				return ec.fieldContext_RepositoryArtifactConnection_edges(ctx, field)
 			case "nodes":
 				return ec.fieldContext_RepositoryArtifactConnection_nodes(ctx, field)
+			case "pageInfo":
+				return ec.fieldContext_RepositoryArtifactConnection_pageInfo(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type RepositoryArtifactConnection", field.Name)
 		},
@@ -2712,7 +3114,48 @@ func (ec *executionContext) _RepositoryArtifactConnection_totalSizeInBytes(ctx c
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.TotalSizeInBytes, nil
+		return ec.resolvers.RepositoryArtifactConnection().TotalSizeInBytes(rctx, obj)
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*int)
+	fc.Result = res
+	return ec.marshalOInt2ᚖint(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepositoryArtifactConnection_totalSizeInBytes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepositoryArtifactConnection",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepositoryArtifactConnection_edges(ctx context.Context, field graphql.CollectedField, obj *RepositoryArtifactConnection) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepositoryArtifactConnection_edges(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Edges, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2724,19 +3167,25 @@ func (ec *executionContext) _RepositoryArtifactConnection_totalSizeInBytes(ctx c
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int64)
+	res := resTmp.([]*RepositoryArtifactEdge)
 	fc.Result = res
-	return ec.marshalNInt2int64(ctx, field.Selections, res)
+	return ec.marshalNRepositoryArtifactEdge2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryArtifactEdge(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_RepositoryArtifactConnection_totalSizeInBytes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_RepositoryArtifactConnection_edges(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "RepositoryArtifactConnection",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "cursor":
+				return ec.fieldContext_RepositoryArtifactEdge_cursor(ctx, field)
+			case "node":
+				return ec.fieldContext_RepositoryArtifactEdge_node(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type RepositoryArtifactEdge", field.Name)
 		},
 	}
 	return fc, nil
@@ -2802,8 +3251,427 @@ func (ec *executionContext) fieldContext_RepositoryArtifactConnection_nodes(ctx
 	return fc, nil
 }
 
-func (ec *executionContext) _StorageBilling_daysLeftInBillingCycle(ctx context.Context, field graphql.CollectedField, obj *StorageBilling) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StorageBilling_daysLeftInBillingCycle(ctx, field)
+func (ec *executionContext) _RepositoryArtifactConnection_pageInfo(ctx context.Context, field graphql.CollectedField, obj *RepositoryArtifactConnection) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepositoryArtifactConnection_pageInfo(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.PageInfo, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(*PageInfo)
+	fc.Result = res
+	return ec.marshalNPageInfo2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐPageInfo(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepositoryArtifactConnection_pageInfo(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepositoryArtifactConnection",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "hasNextPage":
+				return ec.fieldContext_PageInfo_hasNextPage(ctx, field)
+			case "hasPreviousPage":
+				return ec.fieldContext_PageInfo_hasPreviousPage(ctx, field)
+			case "startCursor":
+				return ec.fieldContext_PageInfo_startCursor(ctx, field)
+			case "endCursor":
+				return ec.fieldContext_PageInfo_endCursor(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type PageInfo", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepositoryArtifactEdge_cursor(ctx context.Context, field graphql.CollectedField, obj *RepositoryArtifactEdge) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepositoryArtifactEdge_cursor(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Cursor, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(string)
+	fc.Result = res
+	return ec.marshalNString2string(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepositoryArtifactEdge_cursor(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepositoryArtifactEdge",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _RepositoryArtifactEdge_node(ctx context.Context, field graphql.CollectedField, obj *RepositoryArtifactEdge) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_RepositoryArtifactEdge_node(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.Node, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		return graphql.Null
+	}
+	res := resTmp.(*Artifact)
+	fc.Result = res
+	return ec.marshalOArtifact2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐArtifact(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_RepositoryArtifactEdge_node(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "RepositoryArtifactEdge",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Artifact_id(ctx, field)
+			case "name":
+				return ec.fieldContext_Artifact_name(ctx, field)
+			case "sizeInBytes":
+				return ec.fieldContext_Artifact_sizeInBytes(ctx, field)
+			case "archiveDownloadURL":
+				return ec.fieldContext_Artifact_archiveDownloadURL(ctx, field)
+			case "expired":
+				return ec.fieldContext_Artifact_expired(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_Artifact_createdAt(ctx, field)
+			case "expiresAt":
+				return ec.fieldContext_Artifact_expiresAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Artifact", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SSOAuthorization_authorizedOrganizations(ctx context.Context, field graphql.CollectedField, obj *SSOAuthorization) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SSOAuthorization_authorizedOrganizations(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.AuthorizedOrganizations, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]string)
+	fc.Result = res
+	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_SSOAuthorization_authorizedOrganizations(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SSOAuthorization",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type String does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _SSOAuthorization_unauthorizedOrganizationIds(ctx context.Context, field graphql.CollectedField, obj *SSOAuthorization) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_SSOAuthorization_unauthorizedOrganizationIds(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.UnauthorizedOrganizationIDs, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.([]int64)
+	fc.Result = res
+	return ec.marshalNInt2ᚕint64ᚄ(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_SSOAuthorization_unauthorizedOrganizationIds(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "SSOAuthorization",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _StorageBilling_daysLeftInBillingCycle(ctx context.Context, field graphql.CollectedField, obj *StorageBilling) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StorageBilling_daysLeftInBillingCycle(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.DaysLeftInBillingCycle, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_StorageBilling_daysLeftInBillingCycle(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "StorageBilling",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _StorageBilling_estimatedPaidStorageForMonth(ctx context.Context, field graphql.CollectedField, obj *StorageBilling) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StorageBilling_estimatedPaidStorageForMonth(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.EstimatedPaidStorageForMonth, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(float64)
+	fc.Result = res
+	return ec.marshalNFloat2float64(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_StorageBilling_estimatedPaidStorageForMonth(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "StorageBilling",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Float does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _StorageBilling_estimatedStorageForMonth(ctx context.Context, field graphql.CollectedField, obj *StorageBilling) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_StorageBilling_estimatedStorageForMonth(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.EstimatedStorageForMonth, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(int)
+	fc.Result = res
+	return ec.marshalNInt2int(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_StorageBilling_estimatedStorageForMonth(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "StorageBilling",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ViewerCredential_tokenType(ctx context.Context, field graphql.CollectedField, obj *ViewerCredential) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ViewerCredential_tokenType(ctx, field)
+	if err != nil {
+		return graphql.Null
+	}
+	ctx = graphql.WithFieldContext(ctx, fc)
+	defer func() {
+		if r := recover(); r != nil {
+			ec.Error(ctx, ec.Recover(ctx, r))
+			ret = graphql.Null
+		}
+	}()
+	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
+		ctx = rctx // use context from middleware stack in children
+		return obj.TokenType, nil
+	})
+	if err != nil {
+		ec.Error(ctx, err)
+		return graphql.Null
+	}
+	if resTmp == nil {
+		if !graphql.HasFieldError(ctx, fc) {
+			ec.Errorf(ctx, "must not be null")
+		}
+		return graphql.Null
+	}
+	res := resTmp.(authz.TokenType)
+	fc.Result = res
+	return ec.marshalNTokenType2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚋauthzᚐTokenType(ctx, field.Selections, res)
+}
+
+func (ec *executionContext) fieldContext_ViewerCredential_tokenType(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "ViewerCredential",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type TokenType does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _ViewerCredential_scopes(ctx context.Context, field graphql.CollectedField, obj *ViewerCredential) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ViewerCredential_scopes(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2816,7 +3684,7 @@ func (ec *executionContext) _StorageBilling_daysLeftInBillingCycle(ctx context.C
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.DaysLeftInBillingCycle, nil
+		return obj.Scopes, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2828,26 +3696,26 @@ func (ec *executionContext) _StorageBilling_daysLeftInBillingCycle(ctx context.C
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.([]string)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalNString2ᚕstringᚄ(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StorageBilling_daysLeftInBillingCycle(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ViewerCredential_scopes(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StorageBilling",
+		Object:     "ViewerCredential",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			return nil, errors.New("field of type String does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StorageBilling_estimatedPaidStorageForMonth(ctx context.Context, field graphql.CollectedField, obj *StorageBilling) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StorageBilling_estimatedPaidStorageForMonth(ctx, field)
+func (ec *executionContext) _ViewerCredential_expiresAt(ctx context.Context, field graphql.CollectedField, obj *ViewerCredential) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ViewerCredential_expiresAt(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2860,38 +3728,35 @@ func (ec *executionContext) _StorageBilling_estimatedPaidStorageForMonth(ctx con
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EstimatedPaidStorageForMonth, nil
+		return obj.ExpiresAt, nil
 	})
 	if err != nil {
 		ec.Error(ctx, err)
 		return graphql.Null
 	}
 	if resTmp == nil {
-		if !graphql.HasFieldError(ctx, fc) {
-			ec.Errorf(ctx, "must not be null")
-		}
 		return graphql.Null
 	}
-	res := resTmp.(float64)
+	res := resTmp.(*time.Time)
 	fc.Result = res
-	return ec.marshalNFloat2float64(ctx, field.Selections, res)
+	return ec.marshalOTime2ᚖtimeᚐTime(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StorageBilling_estimatedPaidStorageForMonth(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ViewerCredential_expiresAt(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StorageBilling",
+		Object:     "ViewerCredential",
 		Field:      field,
 		IsMethod:   false,
 		IsResolver: false,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Float does not have child fields")
+			return nil, errors.New("field of type Time does not have child fields")
 		},
 	}
 	return fc, nil
 }
 
-func (ec *executionContext) _StorageBilling_estimatedStorageForMonth(ctx context.Context, field graphql.CollectedField, obj *StorageBilling) (ret graphql.Marshaler) {
-	fc, err := ec.fieldContext_StorageBilling_estimatedStorageForMonth(ctx, field)
+func (ec *executionContext) _ViewerCredential_ssoAuthorization(ctx context.Context, field graphql.CollectedField, obj *ViewerCredential) (ret graphql.Marshaler) {
+	fc, err := ec.fieldContext_ViewerCredential_ssoAuthorization(ctx, field)
 	if err != nil {
 		return graphql.Null
 	}
@@ -2904,7 +3769,7 @@ func (ec *executionContext) _StorageBilling_estimatedStorageForMonth(ctx context
 	}()
 	resTmp, err := ec.ResolverMiddleware(ctx, func(rctx context.Context) (interface{}, error) {
 		ctx = rctx // use context from middleware stack in children
-		return obj.EstimatedStorageForMonth, nil
+		return ec.resolvers.ViewerCredential().SsoAuthorization(rctx, obj)
 	})
 	if err != nil {
 		ec.Error(ctx, err)
@@ -2916,19 +3781,25 @@ func (ec *executionContext) _StorageBilling_estimatedStorageForMonth(ctx context
 		}
 		return graphql.Null
 	}
-	res := resTmp.(int)
+	res := resTmp.(*SSOAuthorization)
 	fc.Result = res
-	return ec.marshalNInt2int(ctx, field.Selections, res)
+	return ec.marshalNSSOAuthorization2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐSSOAuthorization(ctx, field.Selections, res)
 }
 
-func (ec *executionContext) fieldContext_StorageBilling_estimatedStorageForMonth(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+func (ec *executionContext) fieldContext_ViewerCredential_ssoAuthorization(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
-		Object:     "StorageBilling",
+		Object:     "ViewerCredential",
 		Field:      field,
-		IsMethod:   false,
-		IsResolver: false,
+		IsMethod:   true,
+		IsResolver: true,
 		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
-			return nil, errors.New("field of type Int does not have child fields")
+			switch field.Name {
+			case "authorizedOrganizations":
+				return ec.fieldContext_SSOAuthorization_authorizedOrganizations(ctx, field)
+			case "unauthorizedOrganizationIds":
+				return ec.fieldContext_SSOAuthorization_unauthorizedOrganizationIds(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type SSOAuthorization", field.Name)
 		},
 	}
 	return fc, nil
@@ -4748,6 +5619,62 @@ func (ec *executionContext) fieldContext___Type_specifiedByURL(ctx context.Conte
 
 // region    **************************** input.gotpl *****************************
 
+func (ec *executionContext) unmarshalInputOrganizationByLoginsInput(ctx context.Context, obj interface{}) (OrganizationByLoginsInput, error) {
+	var it OrganizationByLoginsInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"Login"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "Login":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("Login"))
+			it.Login, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
+func (ec *executionContext) unmarshalInputRepositoryByNameWithOwnersInput(ctx context.Context, obj interface{}) (RepositoryByNameWithOwnersInput, error) {
+	var it RepositoryByNameWithOwnersInput
+	asMap := map[string]interface{}{}
+	for k, v := range obj.(map[string]interface{}) {
+		asMap[k] = v
+	}
+
+	fieldsInOrder := [...]string{"NameWithOwner"}
+	for _, k := range fieldsInOrder {
+		v, ok := asMap[k]
+		if !ok {
+			continue
+		}
+		switch k {
+		case "NameWithOwner":
+			var err error
+
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("NameWithOwner"))
+			it.NameWithOwner, err = ec.unmarshalNString2string(ctx, v)
+			if err != nil {
+				return it, err
+			}
+		}
+	}
+
+	return it, nil
+}
+
 // endregion **************************** input.gotpl *****************************
 
 // region    ************************** interface.gotpl ***************************
@@ -5029,7 +5956,7 @@ func (ec *executionContext) _Entity(ctx context.Context, sel ast.SelectionSet) g
 		switch field.Name {
 		case "__typename":
 			out.Values[i] = graphql.MarshalString("Entity")
-		case "findOrganizationByLogin":
+		case "findManyOrganizationByLogins":
 			field := field
 
 			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
@@ -5038,10 +5965,7 @@ func (ec *executionContext) _Entity(ctx context.Context, sel ast.SelectionSet) g
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Entity_findOrganizationByLogin(ctx, field)
-				if res == graphql.Null {
-					atomic.AddUint32(&invalids, 1)
-				}
+				res = ec._Entity_findManyOrganizationByLogins(ctx, field)
 				return res
 			}
 
@@ -5052,7 +5976,7 @@ func (ec *executionContext) _Entity(ctx context.Context, sel ast.SelectionSet) g
 			out.Concurrently(i, func() graphql.Marshaler {
 				return rrm(innerCtx)
 			})
-		case "findRepositoryByNameWithOwner":
+		case "findManyRepositoryByNameWithOwners":
 			field := field
 
 			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
@@ -5061,10 +5985,7 @@ func (ec *executionContext) _Entity(ctx context.Context, sel ast.SelectionSet) g
 						ec.Error(ctx, ec.Recover(ctx, r))
 					}
 				}()
-				res = ec._Entity_findRepositoryByNameWithOwner(ctx, field)
-				if res == graphql.Null {
-					atomic.AddUint32(&invalids, 1)
-				}
+				res = ec._Entity_findManyRepositoryByNameWithOwners(ctx, field)
 				return res
 			}
 
@@ -5199,6 +6120,49 @@ func (ec *executionContext) _OrganizationBilling(ctx context.Context, sel ast.Se
 	return out
 }
 
+var pageInfoImplementors = []string{"PageInfo"}
+
+func (ec *executionContext) _PageInfo(ctx context.Context, sel ast.SelectionSet, obj *PageInfo) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, pageInfoImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("PageInfo")
+		case "hasNextPage":
+
+			out.Values[i] = ec._PageInfo_hasNextPage(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "hasPreviousPage":
+
+			out.Values[i] = ec._PageInfo_hasPreviousPage(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "startCursor":
+
+			out.Values[i] = ec._PageInfo_startCursor(ctx, field, obj)
+
+		case "endCursor":
+
+			out.Values[i] = ec._PageInfo_endCursor(ctx, field, obj)
+
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
 var planImplementors = []string{"Plan"}
 
 func (ec *executionContext) _Plan(ctx context.Context, sel ast.SelectionSet, obj *Plan) graphql.Marshaler {
@@ -5300,6 +6264,29 @@ func (ec *executionContext) _Query(ctx context.Context, sel ast.SelectionSet) gr
 				return ec.OperationContext.RootResolverMiddleware(ctx, innerFunc)
 			}
 
+			out.Concurrently(i, func() graphql.Marshaler {
+				return rrm(innerCtx)
+			})
+		case "viewerCredential":
+			field := field
+
+			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Query_viewerCredential(ctx, field)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			}
+
+			rrm := func(ctx context.Context) graphql.Marshaler {
+				return ec.OperationContext.RootResolverMiddleware(ctx, innerFunc)
+			}
+
 			out.Concurrently(i, func() graphql.Marshaler {
 				return rrm(innerCtx)
 			})
@@ -5435,19 +6422,110 @@ func (ec *executionContext) _RepositoryArtifactConnection(ctx context.Context, s
 			out.Values[i] = ec._RepositoryArtifactConnection_totalCount(ctx, field, obj)
 
 			if out.Values[i] == graphql.Null {
-				invalids++
+				atomic.AddUint32(&invalids, 1)
 			}
 		case "totalSizeInBytes":
+			field := field
+
+			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._RepositoryArtifactConnection_totalSizeInBytes(ctx, field, obj)
+				return res
+			}
+
+			out.Concurrently(i, func() graphql.Marshaler {
+				return innerFunc(ctx)
+
+			})
+		case "edges":
 
-			out.Values[i] = ec._RepositoryArtifactConnection_totalSizeInBytes(ctx, field, obj)
+			out.Values[i] = ec._RepositoryArtifactConnection_edges(ctx, field, obj)
 
 			if out.Values[i] == graphql.Null {
-				invalids++
+				atomic.AddUint32(&invalids, 1)
 			}
 		case "nodes":
 
 			out.Values[i] = ec._RepositoryArtifactConnection_nodes(ctx, field, obj)
 
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "pageInfo":
+
+			out.Values[i] = ec._RepositoryArtifactConnection_pageInfo(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var repositoryArtifactEdgeImplementors = []string{"RepositoryArtifactEdge"}
+
+func (ec *executionContext) _RepositoryArtifactEdge(ctx context.Context, sel ast.SelectionSet, obj *RepositoryArtifactEdge) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, repositoryArtifactEdgeImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("RepositoryArtifactEdge")
+		case "cursor":
+
+			out.Values[i] = ec._RepositoryArtifactEdge_cursor(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "node":
+
+			out.Values[i] = ec._RepositoryArtifactEdge_node(ctx, field, obj)
+
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var sSOAuthorizationImplementors = []string{"SSOAuthorization"}
+
+func (ec *executionContext) _SSOAuthorization(ctx context.Context, sel ast.SelectionSet, obj *SSOAuthorization) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, sSOAuthorizationImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("SSOAuthorization")
+		case "authorizedOrganizations":
+
+			out.Values[i] = ec._SSOAuthorization_authorizedOrganizations(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		case "unauthorizedOrganizationIds":
+
+			out.Values[i] = ec._SSOAuthorization_unauthorizedOrganizationIds(ctx, field, obj)
+
 			if out.Values[i] == graphql.Null {
 				invalids++
 			}
@@ -5490,9 +6568,68 @@ func (ec *executionContext) _StorageBilling(ctx context.Context, sel ast.Selecti
 
 			out.Values[i] = ec._StorageBilling_estimatedStorageForMonth(ctx, field, obj)
 
-			if out.Values[i] == graphql.Null {
-				invalids++
-			}
+			if out.Values[i] == graphql.Null {
+				invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch()
+	if invalids > 0 {
+		return graphql.Null
+	}
+	return out
+}
+
+var viewerCredentialImplementors = []string{"ViewerCredential"}
+
+func (ec *executionContext) _ViewerCredential(ctx context.Context, sel ast.SelectionSet, obj *ViewerCredential) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, viewerCredentialImplementors)
+	out := graphql.NewFieldSet(fields)
+	var invalids uint32
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("ViewerCredential")
+		case "tokenType":
+
+			out.Values[i] = ec._ViewerCredential_tokenType(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "scopes":
+
+			out.Values[i] = ec._ViewerCredential_scopes(ctx, field, obj)
+
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&invalids, 1)
+			}
+		case "expiresAt":
+
+			out.Values[i] = ec._ViewerCredential_expiresAt(ctx, field, obj)
+
+		case "ssoAuthorization":
+			field := field
+
+			innerFunc := func(ctx context.Context) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._ViewerCredential_ssoAuthorization(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&invalids, 1)
+				}
+				return res
+			}
+
+			out.Concurrently(i, func() graphql.Marshaler {
+				return innerFunc(ctx)
+
+			})
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -5969,18 +7106,36 @@ func (ec *executionContext) marshalNInt2int64(ctx context.Context, sel ast.Selec
 	return res
 }
 
-func (ec *executionContext) marshalNOrganization2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx context.Context, sel ast.SelectionSet, v Organization) graphql.Marshaler {
-	return ec._Organization(ctx, sel, &v)
+func (ec *executionContext) unmarshalNInt2ᚕint64ᚄ(ctx context.Context, v interface{}) ([]int64, error) {
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]int64, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNInt2int64(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
 }
 
-func (ec *executionContext) marshalNOrganization2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx context.Context, sel ast.SelectionSet, v *Organization) graphql.Marshaler {
-	if v == nil {
-		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
-			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+func (ec *executionContext) marshalNInt2ᚕint64ᚄ(ctx context.Context, sel ast.SelectionSet, v []int64) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNInt2int64(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
 		}
-		return graphql.Null
 	}
-	return ec._Organization(ctx, sel, v)
+
+	return ret
 }
 
 func (ec *executionContext) marshalNOrganizationBilling2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganizationBilling(ctx context.Context, sel ast.SelectionSet, v *OrganizationBilling) graphql.Marshaler {
@@ -5993,18 +7148,36 @@ func (ec *executionContext) marshalNOrganizationBilling2ᚖgithubᚗcomᚋaereal
 	return ec._OrganizationBilling(ctx, sel, v)
 }
 
-func (ec *executionContext) marshalNRepository2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx context.Context, sel ast.SelectionSet, v Repository) graphql.Marshaler {
-	return ec._Repository(ctx, sel, &v)
+func (ec *executionContext) unmarshalNOrganizationByLoginsInput2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganizationByLoginsInputᚄ(ctx context.Context, v interface{}) ([]*OrganizationByLoginsInput, error) {
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]*OrganizationByLoginsInput, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNOrganizationByLoginsInput2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganizationByLoginsInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) unmarshalNOrganizationByLoginsInput2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganizationByLoginsInput(ctx context.Context, v interface{}) (*OrganizationByLoginsInput, error) {
+	res, err := ec.unmarshalInputOrganizationByLoginsInput(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
 }
 
-func (ec *executionContext) marshalNRepository2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx context.Context, sel ast.SelectionSet, v *Repository) graphql.Marshaler {
+func (ec *executionContext) marshalNPageInfo2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐPageInfo(ctx context.Context, sel ast.SelectionSet, v *PageInfo) graphql.Marshaler {
 	if v == nil {
 		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
 			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
 		}
 		return graphql.Null
 	}
-	return ec._Repository(ctx, sel, v)
+	return ec._PageInfo(ctx, sel, v)
 }
 
 func (ec *executionContext) marshalNRepositoryArtifactConnection2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryArtifactConnection(ctx context.Context, sel ast.SelectionSet, v RepositoryArtifactConnection) graphql.Marshaler {
@@ -6021,6 +7194,80 @@ func (ec *executionContext) marshalNRepositoryArtifactConnection2ᚖgithubᚗcom
 	return ec._RepositoryArtifactConnection(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalNRepositoryArtifactEdge2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryArtifactEdge(ctx context.Context, sel ast.SelectionSet, v []*RepositoryArtifactEdge) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalORepositoryArtifactEdge2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryArtifactEdge(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
+func (ec *executionContext) unmarshalNRepositoryByNameWithOwnersInput2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryByNameWithOwnersInputᚄ(ctx context.Context, v interface{}) ([]*RepositoryByNameWithOwnersInput, error) {
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]*RepositoryByNameWithOwnersInput, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNRepositoryByNameWithOwnersInput2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryByNameWithOwnersInput(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) unmarshalNRepositoryByNameWithOwnersInput2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryByNameWithOwnersInput(ctx context.Context, v interface{}) (*RepositoryByNameWithOwnersInput, error) {
+	res, err := ec.unmarshalInputRepositoryByNameWithOwnersInput(ctx, v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNSSOAuthorization2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐSSOAuthorization(ctx context.Context, sel ast.SelectionSet, v SSOAuthorization) graphql.Marshaler {
+	return ec._SSOAuthorization(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNSSOAuthorization2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐSSOAuthorization(ctx context.Context, sel ast.SelectionSet, v *SSOAuthorization) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._SSOAuthorization(ctx, sel, v)
+}
+
 func (ec *executionContext) marshalNStorageBilling2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐStorageBilling(ctx context.Context, sel ast.SelectionSet, v StorageBilling) graphql.Marshaler {
 	return ec._StorageBilling(ctx, sel, &v)
 }
@@ -6050,6 +7297,38 @@ func (ec *executionContext) marshalNString2string(ctx context.Context, sel ast.S
 	return res
 }
 
+func (ec *executionContext) unmarshalNString2ᚕstringᚄ(ctx context.Context, v interface{}) ([]string, error) {
+	var vSlice []interface{}
+	if v != nil {
+		vSlice = graphql.CoerceList(v)
+	}
+	var err error
+	res := make([]string, len(vSlice))
+	for i := range vSlice {
+		ctx := graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i))
+		res[i], err = ec.unmarshalNString2string(ctx, vSlice[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func (ec *executionContext) marshalNString2ᚕstringᚄ(ctx context.Context, sel ast.SelectionSet, v []string) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	for i := range v {
+		ret[i] = ec.marshalNString2string(ctx, sel, v[i])
+	}
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
 func (ec *executionContext) unmarshalNTime2timeᚐTime(ctx context.Context, v interface{}) (time.Time, error) {
 	res, err := graphql.UnmarshalTime(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -6065,6 +7344,36 @@ func (ec *executionContext) marshalNTime2timeᚐTime(ctx context.Context, sel as
 	return res
 }
 
+func (ec *executionContext) unmarshalNTokenType2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚋauthzᚐTokenType(ctx context.Context, v interface{}) (authz.TokenType, error) {
+	tmp, err := graphql.UnmarshalString(v)
+	res := authz.TokenType(tmp)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNTokenType2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚋauthzᚐTokenType(ctx context.Context, sel ast.SelectionSet, v authz.TokenType) graphql.Marshaler {
+	res := graphql.MarshalString(string(v))
+	if res == graphql.Null {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+	}
+	return res
+}
+
+func (ec *executionContext) marshalNViewerCredential2githubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐViewerCredential(ctx context.Context, sel ast.SelectionSet, v ViewerCredential) graphql.Marshaler {
+	return ec._ViewerCredential(ctx, sel, &v)
+}
+
+func (ec *executionContext) marshalNViewerCredential2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐViewerCredential(ctx context.Context, sel ast.SelectionSet, v *ViewerCredential) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._ViewerCredential(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalN_Any2map(ctx context.Context, v interface{}) (map[string]interface{}, error) {
 	res, err := graphql.UnmarshalMap(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -6498,6 +7807,47 @@ func (ec *executionContext) marshalOInt2ᚖint(ctx context.Context, sel ast.Sele
 	return res
 }
 
+func (ec *executionContext) marshalOOrganization2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx context.Context, sel ast.SelectionSet, v []*Organization) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalOOrganization2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
 func (ec *executionContext) marshalOOrganization2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐOrganization(ctx context.Context, sel ast.SelectionSet, v *Organization) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -6512,6 +7862,47 @@ func (ec *executionContext) marshalOPlan2ᚖgithubᚗcomᚋaerealᚋgithubᚑgra
 	return ec._Plan(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalORepository2ᚕᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx context.Context, sel ast.SelectionSet, v []*Repository) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalORepository2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	return ret
+}
+
 func (ec *executionContext) marshalORepository2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepository(ctx context.Context, sel ast.SelectionSet, v *Repository) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -6519,6 +7910,13 @@ func (ec *executionContext) marshalORepository2ᚖgithubᚗcomᚋaerealᚋgithub
 	return ec._Repository(ctx, sel, v)
 }
 
+func (ec *executionContext) marshalORepositoryArtifactEdge2ᚖgithubᚗcomᚋaerealᚋgithubᚑgraphqlᚑproxyᚐRepositoryArtifactEdge(ctx context.Context, sel ast.SelectionSet, v *RepositoryArtifactEdge) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	return ec._RepositoryArtifactEdge(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalOString2string(ctx context.Context, v interface{}) (string, error) {
 	res, err := graphql.UnmarshalString(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -6583,6 +7981,22 @@ func (ec *executionContext) marshalOString2ᚖstring(ctx context.Context, sel as
 	return res
 }
 
+func (ec *executionContext) unmarshalOTime2ᚖtimeᚐTime(ctx context.Context, v interface{}) (*time.Time, error) {
+	if v == nil {
+		return nil, nil
+	}
+	res, err := graphql.UnmarshalTime(v)
+	return &res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOTime2ᚖtimeᚐTime(ctx context.Context, sel ast.SelectionSet, v *time.Time) graphql.Marshaler {
+	if v == nil {
+		return graphql.Null
+	}
+	res := graphql.MarshalTime(*v)
+	return res
+}
+
 func (ec *executionContext) marshalO_Entity2githubᚗcomᚋ99designsᚋgqlgenᚋpluginᚋfederationᚋfedruntimeᚐEntity(ctx context.Context, sel ast.SelectionSet, v fedruntime.Entity) graphql.Marshaler {
 	if v == nil {
 		return graphql.Null
@@ -0,0 +1,199 @@
+// Package federatedtracing attaches Apollo federated traces (ftv1) to the responses for the router asking for them.
+//
+// gqlgen ships apollofederatedtracingv1.Tracer, but the one of the gqlgen version this module depends on
+// does not record the errors of the fields, sets the end time of a field before the field is resolved,
+// and leaves out the original names of the aliased fields, so the router cannot tell which resolver failed or took long.
+// This package builds the same generated.Trace with them.
+package federatedtracing
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/apollofederatedtracingv1/generated"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// IncludeTraceHeader is the request header the router sends to ask for traces.
+	IncludeTraceHeader = "apollo-federation-include-trace"
+	// FormatFTV1 is the only trace format the router asks for.
+	FormatFTV1 = "ftv1"
+	// ExtensionKey is the key of the response extension that Extension reports.
+	ExtensionKey = "ftv1"
+)
+
+type requestedCtxKey struct{}
+
+// Handler marks the requests asking for ftv1 traces so that Extension traces their operations.
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get(IncludeTraceHeader), FormatFTV1) {
+			r = r.WithContext(context.WithValue(r.Context(), requestedCtxKey{}, true))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func requested(ctx context.Context) bool {
+	v, _ := ctx.Value(requestedCtxKey{}).(bool)
+	return v
+}
+
+// Extension records the timings and errors of the resolvers and reports them as a base64 encoded protobuf in the response extensions.
+//
+// It traces only the requests marked by Handler.
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "ApolloFederatedTracingV1"
+}
+
+func (Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !requested(ctx) || !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	start := graphql.GetOperationContext(ctx).Stats.OperationStart
+	if start.IsZero() {
+		start = time.Now()
+	}
+	tb := newTreeBuilder(start)
+	resp := next(context.WithValue(ctx, treeBuilderCtxKey{}, tb))
+	if resp == nil {
+		return resp
+	}
+	tb.addErrors(resp.Errors)
+	encoded, err := tb.encode(time.Now())
+	if err != nil {
+		return resp
+	}
+	if resp.Extensions == nil {
+		resp.Extensions = map[string]interface{}{}
+	}
+	resp.Extensions[ExtensionKey] = encoded
+	return resp
+}
+
+func (Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	tb, ok := ctx.Value(treeBuilderCtxKey{}).(*treeBuilder)
+	fc := graphql.GetFieldContext(ctx)
+	if !ok || fc == nil {
+		return next(ctx)
+	}
+	node := tb.willResolve(fc)
+	res, err := next(ctx)
+	tb.didResolve(node)
+	return res, err
+}
+
+type treeBuilderCtxKey struct{}
+
+// treeBuilder builds the tree of trace nodes along the response paths. Fields can be resolved concurrently.
+type treeBuilder struct {
+	mux   sync.Mutex
+	start time.Time
+	root  *generated.Trace_Node
+	nodes map[string]*generated.Trace_Node
+}
+
+func newTreeBuilder(start time.Time) *treeBuilder {
+	root := &generated.Trace_Node{}
+	return &treeBuilder{start: start, root: root, nodes: map[string]*generated.Trace_Node{"": root}}
+}
+
+func (tb *treeBuilder) willResolve(fc *graphql.FieldContext) *generated.Trace_Node {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	node := tb.node(fc.Path())
+	node.StartTime = tb.sinceStart()
+	node.ParentType = fc.Object
+	if fc.Field.Field != nil {
+		if fc.Field.Definition != nil {
+			node.Type = fc.Field.Definition.Type.String()
+		}
+		if fc.Field.Alias != "" && fc.Field.Alias != fc.Field.Name {
+			node.OriginalFieldName = fc.Field.Name
+		}
+	}
+	return node
+}
+
+func (tb *treeBuilder) didResolve(node *generated.Trace_Node) {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	node.EndTime = tb.sinceStart()
+}
+
+func (tb *treeBuilder) addErrors(errs gqlerror.List) {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	for _, err := range errs {
+		node := tb.node(err.Path)
+		traceErr := &generated.Trace_Error{Message: err.Message}
+		for _, loc := range err.Locations {
+			traceErr.Location = append(traceErr.Location, &generated.Trace_Location{Line: uint32(loc.Line), Column: uint32(loc.Column)})
+		}
+		if b, jsonErr := json.Marshal(err); jsonErr == nil {
+			traceErr.Json = string(b)
+		}
+		node.Error = append(node.Error, traceErr)
+	}
+}
+
+func (tb *treeBuilder) encode(end time.Time) (string, error) {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	trace := &generated.Trace{
+		StartTime:  timestamppb.New(tb.start),
+		EndTime:    timestamppb.New(end),
+		DurationNs: uint64(end.Sub(tb.start).Nanoseconds()),
+		Root:       tb.root,
+	}
+	b, err := proto.Marshal(trace)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// node returns the node at the path creating it and its ancestors if needed. The caller must hold the lock.
+func (tb *treeBuilder) node(path ast.Path) *generated.Trace_Node {
+	key := path.String()
+	if node, ok := tb.nodes[key]; ok {
+		return node
+	}
+	parent := tb.node(path[:len(path)-1])
+	node := &generated.Trace_Node{}
+	switch el := path[len(path)-1].(type) {
+	case ast.PathIndex:
+		node.Id = &generated.Trace_Node_Index{Index: uint32(el)}
+	case ast.PathName:
+		node.Id = &generated.Trace_Node_ResponseName{ResponseName: string(el)}
+	}
+	parent.Child = append(parent.Child, node)
+	tb.nodes[key] = node
+	return node
+}
+
+func (tb *treeBuilder) sinceStart() uint64 {
+	return uint64(time.Since(tb.start).Nanoseconds())
+}
@@ -0,0 +1,135 @@
+package federatedtracing_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/apollofederatedtracingv1/generated"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/aereal/github-graphql-proxy/federatedtracing"
+	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v47/github"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension(t *testing.T) {
+	type testCase struct {
+		name         string
+		includeTrace string
+		status       int
+		body         string
+		wantTrace    bool
+		wantNodes    []string
+	}
+	testCases := []testCase{
+		{"without header", "", http.StatusOK, `{"plan":{"name":"enterprise"}}`, false, nil},
+		{
+			"ftv1",
+			"ftv1",
+			http.StatusOK,
+			`{"plan":{"name":"enterprise"}}`,
+			true,
+			[]string{"org: Organization (Query) alias of test__organization", "org.plan: Plan (Organization)", "org.plan.name: String (Plan)"},
+		},
+		{
+			"error",
+			"ftv1",
+			http.StatusNotFound,
+			`{"message":"Not Found"}`,
+			true,
+			[]string{"org: Organization (Query) alias of test__organization", "org.plan: Plan (Organization) error"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			githubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("content-type", "application/json")
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer githubSrv.Close()
+			githubClient, err := github.NewEnterpriseClient(githubSrv.URL, githubSrv.URL, githubSrv.Client())
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := handler.New(githubgraphqlproxy.NewExecutableSchema(githubgraphqlproxy.Config{Resolvers: resolvers.New(githubClient)}))
+			h.AddTransport(transport.POST{})
+			h.Use(federatedtracing.Extension{})
+			srv := httptest.NewServer(federatedtracing.Handler(h))
+			defer srv.Close()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader(`{"query":"{ org: test__organization(login: \"test-org\") { plan { name } } }"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("content-type", "application/json")
+			if tc.includeTrace != "" {
+				req.Header.Set(federatedtracing.IncludeTraceHeader, tc.includeTrace)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var body struct {
+				Extensions map[string]string `json:"extensions"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			encoded, ok := body.Extensions[federatedtracing.ExtensionKey]
+			if ok != tc.wantTrace {
+				t.Fatalf("ftv1 extension: got=%v want=%v", ok, tc.wantTrace)
+			}
+			if !ok {
+				return
+			}
+			b, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var trace generated.Trace
+			if err := proto.Unmarshal(b, &trace); err != nil {
+				t.Fatal(err)
+			}
+			if trace.DurationNs == 0 || trace.StartTime == nil || trace.EndTime == nil {
+				t.Errorf("trace timings are missing: %v", &trace)
+			}
+			if diff := cmp.Diff(describeNodes("", trace.Root), tc.wantNodes); diff != "" {
+				t.Errorf("nodes (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func describeNodes(path string, node *generated.Trace_Node) []string {
+	var descs []string
+	for _, child := range node.Child {
+		childPath := child.GetResponseName()
+		if path != "" {
+			childPath = path + "." + childPath
+		}
+		desc := fmt.Sprintf("%s: %s (%s)", childPath, child.Type, child.ParentType)
+		if child.OriginalFieldName != "" {
+			desc += " alias of " + child.OriginalFieldName
+		}
+		if child.EndTime < child.StartTime {
+			desc += " ends before start"
+		}
+		if len(child.Error) > 0 {
+			desc += " error"
+		}
+		descs = append(descs, desc)
+		descs = append(descs, describeNodes(childPath, child)...)
+	}
+	return descs
+}
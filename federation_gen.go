@@ -65,6 +65,10 @@ func (ec *executionContext) __resolve_entities(ctx context.Context, representati
 
 	isMulti := func(typeName string) bool {
 		switch typeName {
+		case "Organization":
+			return true
+		case "Repository":
+			return true
 		default:
 			return false
 		}
@@ -80,61 +84,69 @@ func (ec *executionContext) __resolve_entities(ctx context.Context, representati
 		}()
 
 		switch typeName {
-		case "Organization":
-			resolverName, err := entityResolverNameForOrganization(ctx, rep)
-			if err != nil {
-				return fmt.Errorf(`finding resolver for Entity "Organization": %w`, err)
+
+		}
+		return fmt.Errorf("%w: %s", ErrUnknownType, typeName)
+	}
+
+	resolveManyEntities := func(ctx context.Context, typeName string, reps []map[string]interface{}, idx []int) (err error) {
+		// we need to do our own panic handling, because we may be called in a
+		// goroutine, where the usual panic handling can't catch us
+		defer func() {
+			if r := recover(); r != nil {
+				err = ec.Recover(ctx, r)
 			}
-			switch resolverName {
+		}()
+
+		switch typeName {
+
+		case "Organization":
+			_reps := make([]*OrganizationByLoginsInput, len(reps))
 
-			case "findOrganizationByLogin":
+			for i, rep := range reps {
 				id0, err := ec.unmarshalNString2string(ctx, rep["login"])
 				if err != nil {
-					return fmt.Errorf(`unmarshalling param 0 for findOrganizationByLogin(): %w`, err)
+					return errors.New(fmt.Sprintf("Field %s undefined in schema.", "login"))
 				}
-				entity, err := ec.resolvers.Entity().FindOrganizationByLogin(ctx, id0)
-				if err != nil {
-					return fmt.Errorf(`resolving Entity "Organization": %w`, err)
+
+				_reps[i] = &OrganizationByLoginsInput{
+					Login: id0,
 				}
+			}
+
+			entities, err := ec.resolvers.Entity().FindManyOrganizationByLogins(ctx, _reps)
+			if err != nil {
+				return err
+			}
 
+			for i, entity := range entities {
 				list[idx[i]] = entity
-				return nil
 			}
+			return nil
+
 		case "Repository":
-			resolverName, err := entityResolverNameForRepository(ctx, rep)
-			if err != nil {
-				return fmt.Errorf(`finding resolver for Entity "Repository": %w`, err)
-			}
-			switch resolverName {
+			_reps := make([]*RepositoryByNameWithOwnersInput, len(reps))
 
-			case "findRepositoryByNameWithOwner":
+			for i, rep := range reps {
 				id0, err := ec.unmarshalNString2string(ctx, rep["nameWithOwner"])
 				if err != nil {
-					return fmt.Errorf(`unmarshalling param 0 for findRepositoryByNameWithOwner(): %w`, err)
-				}
-				entity, err := ec.resolvers.Entity().FindRepositoryByNameWithOwner(ctx, id0)
-				if err != nil {
-					return fmt.Errorf(`resolving Entity "Repository": %w`, err)
+					return errors.New(fmt.Sprintf("Field %s undefined in schema.", "nameWithOwner"))
 				}
 
-				list[idx[i]] = entity
-				return nil
+				_reps[i] = &RepositoryByNameWithOwnersInput{
+					NameWithOwner: id0,
+				}
 			}
 
-		}
-		return fmt.Errorf("%w: %s", ErrUnknownType, typeName)
-	}
-
-	resolveManyEntities := func(ctx context.Context, typeName string, reps []map[string]interface{}, idx []int) (err error) {
-		// we need to do our own panic handling, because we may be called in a
-		// goroutine, where the usual panic handling can't catch us
-		defer func() {
-			if r := recover(); r != nil {
-				err = ec.Recover(ctx, r)
+			entities, err := ec.resolvers.Entity().FindManyRepositoryByNameWithOwners(ctx, _reps)
+			if err != nil {
+				return err
 			}
-		}()
 
-		switch typeName {
+			for i, entity := range entities {
+				list[idx[i]] = entity
+			}
+			return nil
 
 		default:
 			return errors.New("unknown type: " + typeName)
@@ -201,7 +213,7 @@ func entityResolverNameForOrganization(ctx context.Context, rep map[string]inter
 		if _, ok = m["login"]; !ok {
 			break
 		}
-		return "findOrganizationByLogin", nil
+		return "findManyOrganizationByLogins", nil
 	}
 	return "", fmt.Errorf("%w for Organization", ErrTypeNotFound)
 }
@@ -218,7 +230,7 @@ func entityResolverNameForRepository(ctx context.Context, rep map[string]interfa
 		if _, ok = m["nameWithOwner"]; !ok {
 			break
 		}
-		return "findRepositoryByNameWithOwner", nil
+		return "findManyRepositoryByNameWithOwners", nil
 	}
 	return "", fmt.Errorf("%w for Repository", ErrTypeNotFound)
 }
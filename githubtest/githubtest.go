@@ -0,0 +1,273 @@
+// Package githubtest provides a fake GitHub REST API server for tests.
+//
+// Routes are matched in the order they are registered. Each route answers its responses in sequence
+// and keeps answering the last one, so retries and pagination can be scripted.
+package githubtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v47/github"
+)
+
+// APIPathPrefix is the path prefix of the REST API of GitHub Enterprise Server that the clients of Server use.
+const APIPathPrefix = "/api/v3"
+
+// Route is a scripted endpoint of Server.
+//
+// It can be decoded from JSON fixtures.
+type Route struct {
+	// Method defaults to GET.
+	Method string `json:"method,omitempty"`
+	// Path is a template such as /repos/{owner}/{repo}/actions/artifacts without APIPathPrefix.
+	Path string `json:"path"`
+	// Query lists the query parameters the request must have.
+	Query url.Values `json:"query,omitempty"`
+	// Header lists the headers the request must have.
+	Header http.Header `json:"header,omitempty"`
+	// Responses are answered in sequence. The last one is repeated once all of them are answered.
+	Responses []*Response `json:"responses"`
+
+	mux   sync.Mutex
+	calls int
+}
+
+// GET returns a route of GET requests to the path.
+func GET(path string, responses ...*Response) *Route {
+	return &Route{Method: http.MethodGet, Path: path, Responses: responses}
+}
+
+// WithQuery adds the query parameter the request must have.
+func (r *Route) WithQuery(key, value string) *Route {
+	if r.Query == nil {
+		r.Query = url.Values{}
+	}
+	r.Query.Add(key, value)
+	return r
+}
+
+// WithHeader adds the header the request must have.
+func (r *Route) WithHeader(key, value string) *Route {
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
+	r.Header.Add(key, value)
+	return r
+}
+
+// Calls returns how many requests the route answered.
+func (r *Route) Calls() int {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.calls
+}
+
+func (r *Route) method() string {
+	if r.Method == "" {
+		return http.MethodGet
+	}
+	return r.Method
+}
+
+func (r *Route) match(req *http.Request, path string) (map[string]string, bool) {
+	if req.Method != r.method() {
+		return nil, false
+	}
+	params, ok := matchPath(r.Path, path)
+	if !ok {
+		return nil, false
+	}
+	query := req.URL.Query()
+	for k, vs := range r.Query {
+		if !reflect.DeepEqual(query[k], vs) {
+			return nil, false
+		}
+	}
+	for k := range r.Header {
+		if req.Header.Get(k) != r.Header.Get(k) {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (r *Route) next() *Response {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	i := r.calls
+	r.calls++
+	if len(r.Responses) == 0 {
+		return &Response{}
+	}
+	if i >= len(r.Responses) {
+		i = len(r.Responses) - 1
+	}
+	return r.Responses[i]
+}
+
+func matchPath(template, path string) (map[string]string, bool) {
+	want := strings.Split(strings.Trim(template, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, seg := range want {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			if got[i] == "" {
+				return nil, false
+			}
+			params[seg[1:len(seg)-1]] = got[i]
+			continue
+		}
+		if seg != got[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Response is a scripted response of Route.
+type Response struct {
+	// Status defaults to 200.
+	Status int         `json:"status,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	// Body is encoded in JSON.
+	Body any `json:"body,omitempty"`
+}
+
+// JSON returns a response of the status and the body encoded in JSON.
+func JSON(status int, body any) *Response {
+	return &Response{Status: status, Body: body}
+}
+
+// WithHeader adds the header to the response.
+func (r *Response) WithHeader(key, value string) *Response {
+	if r.Header == nil {
+		r.Header = http.Header{}
+	}
+	r.Header.Add(key, value)
+	return r
+}
+
+// Request is a request Server received.
+type Request struct {
+	Method string
+	// Path is the path without APIPathPrefix.
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// Params are the values of the placeholders in the path template of the matched route.
+	Params map[string]string
+	// Route is the matched route or nil.
+	Route *Route
+}
+
+// Server is a fake GitHub REST API server.
+type Server struct {
+	*httptest.Server
+
+	t        testing.TB
+	mux      sync.Mutex
+	routes   []*Route
+	requests []*Request
+}
+
+// NewServer starts a server that answers the routes. It is closed when the test finishes.
+//
+// A request that matches no route fails the test and is answered with 501.
+func NewServer(t testing.TB, routes ...*Route) *Server {
+	t.Helper()
+	s := &Server{t: t, routes: routes}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle adds the routes.
+func (s *Server) Handle(routes ...*Route) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.routes = append(s.routes, routes...)
+}
+
+// APIBaseURL returns the base URL of the REST API.
+func (s *Server) APIBaseURL() string {
+	return s.URL + APIPathPrefix + "/"
+}
+
+// Client returns a new client of the server. The wrappers decorate the transport from the innermost.
+//
+// The transport is a copy of the one of httptest.Server, so the wrappers do not leak into the other clients.
+func (s *Server) Client(wrappers ...func(http.RoundTripper) http.RoundTripper) *github.Client {
+	s.t.Helper()
+	var rt http.RoundTripper = s.Server.Client().Transport.(*http.Transport).Clone()
+	for _, wrap := range wrappers {
+		rt = wrap(rt)
+	}
+	client, err := github.NewEnterpriseClient(s.APIBaseURL(), s.APIBaseURL(), &http.Client{Transport: rt})
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return client
+}
+
+// Requests returns the requests the server received in order.
+func (s *Server) Requests() []*Request {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]*Request(nil), s.requests...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := strings.TrimPrefix(r.URL.Path, APIPathPrefix)
+	req := &Request{Method: r.Method, Path: path, Query: r.URL.Query(), Header: r.Header.Clone(), Body: body}
+	s.mux.Lock()
+	for _, route := range s.routes {
+		if params, ok := route.match(r, path); ok {
+			req.Params = params
+			req.Route = route
+			break
+		}
+	}
+	s.requests = append(s.requests, req)
+	s.mux.Unlock()
+
+	if req.Route == nil {
+		s.t.Errorf("githubtest: no route matches %s %s", r.Method, r.URL.RequestURI())
+		writeJSON(w, &Response{Status: http.StatusNotImplemented, Body: map[string]string{"message": "no route matches"}})
+		return
+	}
+	writeJSON(w, req.Route.next())
+}
+
+func writeJSON(w http.ResponseWriter, resp *Response) {
+	b, err := json.Marshal(resp.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("githubtest: cannot encode body: %s", err), http.StatusInternalServerError)
+		return
+	}
+	for k, vs := range resp.Header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	w.Header().Set("content-type", "application/json")
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
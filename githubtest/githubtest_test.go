@@ -0,0 +1,158 @@
+package githubtest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/aereal/github-graphql-proxy/githubtest"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v47/github"
+)
+
+func TestServer(t *testing.T) {
+	artifacts := githubtest.GET("/repos/{owner}/{repo}/actions/artifacts",
+		githubtest.JSON(http.StatusOK, &github.ArtifactList{TotalCount: github.Int64(1)}),
+	).WithQuery("per_page", "100")
+	org := githubtest.GET("/orgs/{org}",
+		githubtest.JSON(http.StatusBadGateway, map[string]string{"message": "oops"}).WithHeader("x-github-request-id", "ABCD:1"),
+		githubtest.JSON(http.StatusOK, &github.Organization{Login: github.String("test-org")}),
+	)
+	srv := githubtest.NewServer(t, artifacts, org)
+	client := srv.Client()
+	ctx := context.Background()
+
+	list, _, err := client.Actions.ListArtifacts(ctx, "test-org", "test-repo", &github.ListOptions{PerPage: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := list.GetTotalCount(); got != 1 {
+		t.Errorf("total count: got=%d", got)
+	}
+	_, resp, err := client.Organizations.Get(ctx, "test-org")
+	if err == nil || resp.StatusCode != http.StatusBadGateway || resp.Header.Get("x-github-request-id") != "ABCD:1" {
+		t.Errorf("first response: err=%v resp=%v", err, resp)
+	}
+	for i := 0; i < 2; i++ {
+		got, _, err := client.Organizations.Get(ctx, "test-org")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.GetLogin() != "test-org" {
+			t.Errorf("login: got=%q", got.GetLogin())
+		}
+	}
+
+	if got := artifacts.Calls(); got != 1 {
+		t.Errorf("calls of artifacts: got=%d", got)
+	}
+	if got := org.Calls(); got != 3 {
+		t.Errorf("calls of org: got=%d", got)
+	}
+	var got []string
+	for _, req := range srv.Requests() {
+		got = append(got, fmt.Sprintf("%s %s %v", req.Method, req.Path, req.Params))
+	}
+	want := []string{
+		"GET /repos/test-org/test-repo/actions/artifacts map[owner:test-org repo:test-repo]",
+		"GET /orgs/test-org map[org:test-org]",
+		"GET /orgs/test-org map[org:test-org]",
+		"GET /orgs/test-org map[org:test-org]",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("requests (-got, +want):\n%s", diff)
+	}
+}
+
+func TestServer_Client(t *testing.T) {
+	srv := githubtest.NewServer(t, githubtest.GET("/orgs/{org}", githubtest.JSON(http.StatusOK, &github.Organization{})))
+	var calls int
+	client := srv.Client(func(rt http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return rt.RoundTrip(r)
+		})
+	})
+	if _, ok := srv.Server.Client().Transport.(*http.Transport); !ok {
+		t.Errorf("the transport of httptest.Server is wrapped: %T", srv.Server.Client().Transport)
+	}
+	if _, _, err := srv.Client().Organizations.Get(context.Background(), "test-org"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("the wrapper decorates the other client: calls=%d", calls)
+	}
+	if _, _, err := client.Organizations.Get(context.Background(), "test-org"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("calls: got=%d want=1", calls)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestServer_match(t *testing.T) {
+	type testCase struct {
+		name      string
+		route     *githubtest.Route
+		method    string
+		path      string
+		header    http.Header
+		wantMatch bool
+	}
+	testCases := []testCase{
+		{"path template", githubtest.GET("/orgs/{org}/settings/billing/actions"), http.MethodGet, "/api/v3/orgs/test-org/settings/billing/actions", nil, true},
+		{"extra segment", githubtest.GET("/orgs/{org}"), http.MethodGet, "/api/v3/orgs/test-org/settings", nil, false},
+		{"method", githubtest.GET("/orgs/{org}"), http.MethodPost, "/api/v3/orgs/test-org", nil, false},
+		{"query", githubtest.GET("/orgs/{org}").WithQuery("page", "2"), http.MethodGet, "/api/v3/orgs/test-org?page=1", nil, false},
+		{"header", githubtest.GET("/orgs/{org}").WithHeader("authorization", "token abc"), http.MethodGet, "/api/v3/orgs/test-org", http.Header{"Authorization": {"token abc"}}, true},
+		{"header mismatch", githubtest.GET("/orgs/{org}").WithHeader("authorization", "token abc"), http.MethodGet, "/api/v3/orgs/test-org", nil, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := &recordingT{TB: t}
+			srv := githubtest.NewServer(rt, tc.route)
+			req, err := http.NewRequest(tc.method, srv.URL+tc.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header = tc.header
+			resp, err := srv.Server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if gotMatch := resp.StatusCode != http.StatusNotImplemented; gotMatch != tc.wantMatch {
+				t.Errorf("match: got=%v want=%v", gotMatch, tc.wantMatch)
+			}
+			if gotFailed := len(rt.errors()) > 0; gotFailed == tc.wantMatch {
+				t.Errorf("test failures: %v", rt.errors())
+			}
+		})
+	}
+}
+
+type recordingT struct {
+	testing.TB
+	mux  sync.Mutex
+	errs []string
+}
+
+func (t *recordingT) Errorf(format string, args ...any) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func (t *recordingT) errors() []string {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	return t.errs
+}
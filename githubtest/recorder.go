@@ -0,0 +1,337 @@
+package githubtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v47/github"
+)
+
+const (
+	// RecordEnv is the environment variable that makes recorders call the real GitHub and overwrite their cassettes.
+	RecordEnv = "GITHUBTEST_RECORD"
+	// TokenEnv is the environment variable of the token that recorders use to call the real GitHub.
+	TokenEnv = "GITHUB_TOKEN"
+
+	redactedValue = "REDACTED"
+)
+
+var (
+	ErrNoInteraction = errors.New("no recorded interaction matches")
+
+	tokenPattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})\b`)
+
+	// recordedRequestHeaders are the only request headers kept in cassettes.
+	recordedRequestHeaders = []string{"Accept", "Content-Type", "X-Github-Api-Version"}
+	// redactedResponseHeaders are the response headers whose values are masked in cassettes.
+	redactedResponseHeaders = []string{"Set-Cookie", "X-Github-Sso", "X-Oauth-Client-Id"}
+)
+
+type RecorderMode int
+
+const (
+	// ModeReplay serves the interactions in the cassette and fails on requests not recorded.
+	ModeReplay RecorderMode = iota
+	// ModeRecord calls the real GitHub and writes the interactions to the cassette when the test finishes.
+	ModeRecord
+)
+
+// Cassette is the interactions recorded in a file.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	// BodyEncoding tells how Body holds the response body. Body is absent if the response has no body.
+	BodyEncoding BodyEncoding    `json:"bodyEncoding,omitempty"`
+	Body         json.RawMessage `json:"body,omitempty"`
+}
+
+// BodyEncoding is how a response body is stored in cassettes.
+type BodyEncoding string
+
+const (
+	// BodyEncodingJSON stores the body as is because it is valid JSON.
+	BodyEncodingJSON BodyEncoding = "json"
+	// BodyEncodingText stores the body as a JSON string because it is valid UTF-8 but not JSON.
+	BodyEncodingText BodyEncoding = "text"
+	// BodyEncodingBase64 stores the body as a JSON string of base64 because it is not UTF-8.
+	BodyEncodingBase64 BodyEncoding = "base64"
+)
+
+type RecorderOption func(*Recorder)
+
+// WithRecorderMode overrides the mode decided by RecordEnv.
+func WithRecorderMode(mode RecorderMode) RecorderOption {
+	return func(r *Recorder) { r.mode = mode }
+}
+
+// WithRecorderBase configures the transport to call the real GitHub in ModeRecord.
+func WithRecorderBase(base http.RoundTripper) RecorderOption {
+	return func(r *Recorder) { r.base = base }
+}
+
+// WithRedactedHeaders adds the response headers whose values are masked in cassettes.
+func WithRedactedHeaders(names ...string) RecorderOption {
+	return func(r *Recorder) { r.redactedHeaders = append(r.redactedHeaders, names...) }
+}
+
+// Recorder is http.RoundTripper that records the exchanges with GitHub into a cassette or replays them offline.
+//
+// Only the path and the query of the URLs are matched, so the cassettes work against any host.
+// Authorization and other credentials are never written, and token-like strings in the bodies are masked.
+type Recorder struct {
+	t               testing.TB
+	path            string
+	mode            RecorderMode
+	base            http.RoundTripper
+	redactedHeaders []string
+
+	mux      sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// NewRecorder returns a recorder of the cassette at path such as testdata/cassettes/org.json.
+//
+// It is in ModeRecord if RecordEnv is set, or ModeReplay otherwise.
+func NewRecorder(t testing.TB, path string, opts ...RecorderOption) *Recorder {
+	t.Helper()
+	r := &Recorder{
+		t:               t,
+		path:            path,
+		base:            http.DefaultTransport,
+		redactedHeaders: append([]string(nil), redactedResponseHeaders...),
+		cassette:        &Cassette{},
+	}
+	if os.Getenv(RecordEnv) != "" {
+		r.mode = ModeRecord
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	switch r.mode {
+	case ModeReplay:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("githubtest: read cassette: %s; set %s=1 to record it", err, RecordEnv)
+		}
+		if err := json.Unmarshal(b, r.cassette); err != nil {
+			t.Fatalf("githubtest: decode cassette %s: %s", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	case ModeRecord:
+		t.Cleanup(func() {
+			if err := r.save(); err != nil {
+				t.Errorf("githubtest: save cassette: %s", err)
+			}
+		})
+	}
+	return r
+}
+
+// Client returns a client of GitHub.com through the recorder.
+func (r *Recorder) Client() *github.Client {
+	return github.NewClient(&http.Client{Transport: r})
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRecordedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		r.used[i] = true
+		resp, err := interaction.Response.toHTTP(req)
+		if err != nil {
+			err = fmt.Errorf("%s %s in %s: %w", recorded.Method, recorded.URL, r.path, err)
+			r.t.Errorf("githubtest: %s", err)
+			return nil, err
+		}
+		return resp, nil
+	}
+	err := fmt.Errorf("%w: %s %s in %s", ErrNoInteraction, recorded.Method, recorded.URL, r.path)
+	r.t.Errorf("githubtest: %s", err)
+	return nil, err
+}
+
+func (r *Recorder) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if recorded.Body != "" {
+		out.Body = io.NopCloser(bytes.NewReader([]byte(recorded.Body)))
+	}
+	if token := os.Getenv(TokenEnv); token != "" && out.Header.Get("authorization") == "" {
+		out.Header.Set("authorization", "Bearer "+token)
+	}
+	resp, err := r.base.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	header := resp.Header.Clone()
+	for _, name := range r.redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redactedValue)
+		}
+	}
+	recordedResp := RecordedResponse{Status: resp.StatusCode, Header: header}
+	if err := recordedResp.setBody(tokenPattern.ReplaceAll(body, []byte(redactedValue))); err != nil {
+		return nil, err
+	}
+	recorded.Body = tokenPattern.ReplaceAllString(recorded.Body, redactedValue)
+	r.mux.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{Request: recorded, Response: recordedResp})
+	r.mux.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) save() error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+func newRecordedRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{Method: req.Method, URL: req.URL.RequestURI()}
+	for _, name := range recordedRequestHeaders {
+		if v := req.Header.Get(name); v != "" {
+			if recorded.Header == nil {
+				recorded.Header = http.Header{}
+			}
+			recorded.Header.Set(name, v)
+		}
+	}
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return recorded, err
+		}
+		recorded.Body = string(b)
+	}
+	return recorded, nil
+}
+
+func (r RecordedRequest) matches(other RecordedRequest) bool {
+	return r.Method == other.Method && r.URL == other.URL && r.Body == tokenPattern.ReplaceAllString(other.Body, redactedValue)
+}
+
+func (r *RecordedResponse) setBody(body []byte) error {
+	var err error
+	switch {
+	case len(body) == 0:
+		r.BodyEncoding, r.Body = "", nil
+	case json.Valid(body):
+		r.BodyEncoding, r.Body = BodyEncodingJSON, json.RawMessage(body)
+	case utf8.Valid(body):
+		r.BodyEncoding = BodyEncodingText
+		r.Body, err = json.Marshal(string(body))
+	default:
+		r.BodyEncoding = BodyEncodingBase64
+		r.Body, err = json.Marshal(body)
+	}
+	return err
+}
+
+func (r RecordedResponse) body() ([]byte, error) {
+	switch r.BodyEncoding {
+	case "":
+		if len(r.Body) > 0 {
+			return nil, errors.New("body without bodyEncoding")
+		}
+		return nil, nil
+	case BodyEncodingJSON:
+		// cassettes are indented, so the body is compacted back as GitHub sends it
+		buf := new(bytes.Buffer)
+		if err := json.Compact(buf, r.Body); err != nil {
+			return nil, fmt.Errorf("decode JSON body: %w", err)
+		}
+		return buf.Bytes(), nil
+	case BodyEncodingText:
+		var s string
+		if err := json.Unmarshal(r.Body, &s); err != nil {
+			return nil, fmt.Errorf("decode text body: %w", err)
+		}
+		return []byte(s), nil
+	case BodyEncodingBase64:
+		var b []byte
+		if err := json.Unmarshal(r.Body, &b); err != nil {
+			return nil, fmt.Errorf("decode base64 body: %w", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unknown bodyEncoding %q", r.BodyEncoding)
+	}
+}
+
+func (r RecordedResponse) toHTTP(req *http.Request) (*http.Response, error) {
+	body, err := r.body()
+	if err != nil {
+		return nil, err
+	}
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -3,18 +3,25 @@ package githubgraphqlproxy_test
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/aereal/github-graphql-proxy/githubtest"
+	"github.com/aereal/github-graphql-proxy/ratelimit"
 	"github.com/aereal/github-graphql-proxy/resolvers"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-github/v47/github"
@@ -33,13 +40,33 @@ var (
 			}
 		}
 	`
+	artifactsQuery = `
+		query artifactsQuery($owner: String!, $name: String!, $first: Int, $after: String, $page: Int) {
+			test__repository(owner: $owner, name: $name) {
+				artifacts(first: $first, after: $after, page: $page) {
+					totalCount
+					totalSizeInBytes
+					edges { cursor node { id } }
+					pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+				}
+			}
+		}
+	`
+	entitiesQuery = `
+		query entitiesQuery($representations: [_Any!]!) {
+			_entities(representations: $representations) {
+				... on Repository { nameWithOwner artifacts { totalCount } }
+				... on Organization { login }
+			}
+		}
+	`
 )
 
 func TestHandler(t *testing.T) {
 	org := "test-org"
 	type testCase struct {
 		name                string
-		responseDefinition  mockAPIResponseList
+		routes              []*githubtest.Route
 		graphqlParams       *graphql.RawParams
 		wantData            map[string]any
 		wantExtension       map[string]any
@@ -48,16 +75,16 @@ func TestHandler(t *testing.T) {
 	testCases := []testCase{
 		{
 			"ok",
-			mockAPIResponseList{
+			[]*githubtest.Route{
 				{
-					urlPath: fmt.Sprintf("/api/v3/orgs/%s", org),
-					body: &github.Organization{
+					Path: fmt.Sprintf("/orgs/%s", org),
+					Responses: []*githubtest.Response{{Header: rateLimitHeader(4999), Body: &github.Organization{
 						Plan: &github.Plan{
 							Name:        github.String("enterprise"),
 							Seats:       github.Int(5),
 							FilledSeats: github.Int(3),
 						},
-					},
+					}}},
 				},
 			},
 			&graphql.RawParams{
@@ -65,7 +92,7 @@ func TestHandler(t *testing.T) {
 				Variables: map[string]any{"org": org},
 			},
 			map[string]any{"test__organization": map[string]any{"plan": map[string]any{"filledSeats": float64(3), "seats": float64(5), "name": "enterprise"}}},
-			nil,
+			rateLimitExtension(1, 0, &ratelimit.Rate{Limit: 5000, Remaining: 4999, Reset: time.Unix(1660000000, 0), Resource: "core"}),
 			func(t *testing.T, errs gqlerror.List) {
 				t.Helper()
 				if msg := errs.Error(); msg != "" {
@@ -75,16 +102,15 @@ func TestHandler(t *testing.T) {
 		},
 		{
 			"error from GitHub API",
-			mockAPIResponseList{
+			[]*githubtest.Route{
 				{
-					urlPath: fmt.Sprintf("/api/v3/orgs/%s", org),
-					code:    http.StatusServiceUnavailable,
-					body:    map[string]any{"message": "oops"},
+					Path:      fmt.Sprintf("/orgs/%s", org),
+					Responses: []*githubtest.Response{{Status: http.StatusServiceUnavailable, Header: http.Header{"X-Github-Request-Id": {"ABCD:1234"}}, Body: map[string]any{"message": "oops"}}},
 				},
 			},
 			&graphql.RawParams{Query: query, Variables: map[string]any{"org": org}},
 			map[string]any{"test__organization": map[string]any{"plan": nil}},
-			nil,
+			rateLimitExtension(1, 0),
 			func(t *testing.T, errs gqlerror.List) {
 				t.Helper()
 				msg := errs.Error()
@@ -95,14 +121,63 @@ func TestHandler(t *testing.T) {
 				if !(strings.HasPrefix(msg, prefix) && strings.HasSuffix(msg, suffix)) {
 					t.Errorf("error:\n%q", msg)
 				}
+				assertErrorExtensions(t, errs, map[string]any{"code": resolvers.CodeUpstreamUnavailable, "upstreamStatus": float64(503), "githubRequestId": "ABCD:1234"})
+			},
+		},
+		{
+			"organization not found",
+			[]*githubtest.Route{
+				{
+					Path:      fmt.Sprintf("/orgs/%s", org),
+					Responses: []*githubtest.Response{{Status: http.StatusNotFound, Body: map[string]any{"message": "Not Found"}}},
+				},
+			},
+			&graphql.RawParams{Query: query, Variables: map[string]any{"org": org}},
+			map[string]any{"test__organization": map[string]any{"plan": nil}},
+			rateLimitExtension(1, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				assertErrorExtensions(t, errs, map[string]any{"code": resolvers.CodeNotFound, "upstreamStatus": float64(404)})
+			},
+		},
+		{
+			"bad credentials",
+			[]*githubtest.Route{
+				{
+					Path:      fmt.Sprintf("/orgs/%s", org),
+					Responses: []*githubtest.Response{{Status: http.StatusUnauthorized, Body: map[string]any{"message": "Bad credentials"}}},
+				},
+			},
+			&graphql.RawParams{Query: query, Variables: map[string]any{"org": org}},
+			map[string]any{"test__organization": map[string]any{"plan": nil}},
+			rateLimitExtension(1, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				assertErrorExtensions(t, errs, map[string]any{"code": resolvers.CodeUnauthenticated, "upstreamStatus": float64(401)})
+			},
+		},
+		{
+			"rate limit exceeded",
+			[]*githubtest.Route{
+				{
+					Path:      fmt.Sprintf("/orgs/%s", org),
+					Responses: []*githubtest.Response{{Status: http.StatusForbidden, Header: rateLimitHeader(0), Body: map[string]any{"message": "API rate limit exceeded"}}},
+				},
+			},
+			&graphql.RawParams{Query: query, Variables: map[string]any{"org": org}},
+			map[string]any{"test__organization": map[string]any{"plan": nil}},
+			rateLimitExtension(1, 0, &ratelimit.Rate{Limit: 5000, Remaining: 0, Reset: time.Unix(1660000000, 0), Resource: "core"}),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				assertErrorExtensions(t, errs, map[string]any{"code": resolvers.CodeRateLimited, "upstreamStatus": float64(403)})
 			},
 		},
 		{
 			"successfully got the org but got nothing plan",
-			mockAPIResponseList{
+			[]*githubtest.Route{
 				{
-					urlPath: fmt.Sprintf("/api/v3/orgs/%s", org),
-					body:    &github.Organization{},
+					Path:      fmt.Sprintf("/orgs/%s", org),
+					Responses: []*githubtest.Response{{Body: &github.Organization{}}},
 				},
 			},
 			&graphql.RawParams{
@@ -110,7 +185,7 @@ func TestHandler(t *testing.T) {
 				Variables: map[string]any{"org": org},
 			},
 			map[string]any{"test__organization": map[string]any{"plan": nil}},
-			nil,
+			rateLimitExtension(1, 0),
 			func(t *testing.T, errs gqlerror.List) {
 				t.Helper()
 				if msg := errs.Error(); msg != fmt.Sprintf("input: test__organization.plan %s\n", resolvers.ErrOrganizationPlanIsNil) {
@@ -118,14 +193,284 @@ func TestHandler(t *testing.T) {
 				}
 			},
 		},
+		{
+			"artifacts across pages",
+			[]*githubtest.Route{
+				{
+					Path:      "/repos/test-org/test-repo/actions/artifacts",
+					Query:     url.Values{"page": {"1"}, "per_page": {"100"}},
+					Responses: []*githubtest.Response{{Header: rateLimitHeader(4999), Body: &github.ArtifactList{TotalCount: github.Int64(150), Artifacts: newArtifacts(0, 100, 1)}}},
+				},
+				{
+					Path:      "/repos/test-org/test-repo/actions/artifacts",
+					Query:     url.Values{"page": {"2"}, "per_page": {"100"}},
+					Responses: []*githubtest.Response{{Header: rateLimitHeader(4998), Body: &github.ArtifactList{TotalCount: github.Int64(150), Artifacts: newArtifacts(100, 50, 2)}}},
+				},
+			},
+			&graphql.RawParams{
+				Query:     artifactsQuery,
+				Variables: map[string]any{"owner": "test-org", "name": "test-repo", "first": 2, "after": artifactCursor(98, 98)},
+			},
+			map[string]any{
+				"test__repository": map[string]any{
+					"artifacts": map[string]any{
+						"totalCount":       float64(150),
+						"totalSizeInBytes": float64(200),
+						"edges": []any{
+							map[string]any{"cursor": artifactCursor(99, 99), "node": map[string]any{"id": float64(99)}},
+							map[string]any{"cursor": artifactCursor(100, 100), "node": map[string]any{"id": float64(100)}},
+						},
+						"pageInfo": map[string]any{
+							"hasNextPage":     true,
+							"hasPreviousPage": true,
+							"startCursor":     artifactCursor(99, 99),
+							"endCursor":       artifactCursor(100, 100),
+						},
+					},
+				},
+			},
+			rateLimitExtension(2, 0, &ratelimit.Rate{Limit: 5000, Remaining: 4998, Reset: time.Unix(1660000000, 0), Resource: "core"}),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				if msg := errs.Error(); msg != "" {
+					t.Errorf("errors:\n%s", msg)
+				}
+			},
+		},
+		{
+			"artifacts after the cursor shifted by new artifacts",
+			[]*githubtest.Route{
+				{
+					Path:      "/repos/test-org/test-repo/actions/artifacts",
+					Query:     url.Values{"page": {"1"}, "per_page": {"100"}},
+					Responses: []*githubtest.Response{{Header: rateLimitHeader(4999), Body: &github.ArtifactList{TotalCount: github.Int64(152), Artifacts: append(newArtifacts(1000, 2, 1), newArtifacts(0, 98, 1)...)}}},
+				},
+				{
+					Path:      "/repos/test-org/test-repo/actions/artifacts",
+					Query:     url.Values{"page": {"2"}, "per_page": {"100"}},
+					Responses: []*githubtest.Response{{Header: rateLimitHeader(4998), Body: &github.ArtifactList{TotalCount: github.Int64(152), Artifacts: newArtifacts(98, 52, 2)}}},
+				},
+			},
+			&graphql.RawParams{
+				Query:     artifactsQuery,
+				Variables: map[string]any{"owner": "test-org", "name": "test-repo", "first": 2, "after": artifactCursor(98, 98)},
+			},
+			map[string]any{
+				"test__repository": map[string]any{
+					"artifacts": map[string]any{
+						"totalCount":       float64(152),
+						"totalSizeInBytes": float64(204),
+						"edges": []any{
+							map[string]any{"cursor": artifactCursor(101, 99), "node": map[string]any{"id": float64(99)}},
+							map[string]any{"cursor": artifactCursor(102, 100), "node": map[string]any{"id": float64(100)}},
+						},
+						"pageInfo": map[string]any{
+							"hasNextPage":     true,
+							"hasPreviousPage": true,
+							"startCursor":     artifactCursor(101, 99),
+							"endCursor":       artifactCursor(102, 100),
+						},
+					},
+				},
+			},
+			rateLimitExtension(2, 0, &ratelimit.Rate{Limit: 5000, Remaining: 4998, Reset: time.Unix(1660000000, 0), Resource: "core"}),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				if msg := errs.Error(); msg != "" {
+					t.Errorf("errors:\n%s", msg)
+				}
+			},
+		},
+		{
+			"artifacts with deprecated page",
+			[]*githubtest.Route{
+				{
+					Path:      "/repos/test-org/test-repo/actions/artifacts",
+					Query:     url.Values{"page": {"1"}, "per_page": {"100"}},
+					Responses: []*githubtest.Response{{Body: &github.ArtifactList{TotalCount: github.Int64(5), Artifacts: newArtifacts(1, 5, 1)}}},
+				},
+			},
+			&graphql.RawParams{
+				Query:     artifactsQuery,
+				Variables: map[string]any{"owner": "test-org", "name": "test-repo", "first": 2, "page": 2},
+			},
+			map[string]any{
+				"test__repository": map[string]any{
+					"artifacts": map[string]any{
+						"totalCount":       float64(5),
+						"totalSizeInBytes": float64(5),
+						"edges": []any{
+							map[string]any{"cursor": artifactCursor(2, 3), "node": map[string]any{"id": float64(3)}},
+							map[string]any{"cursor": artifactCursor(3, 4), "node": map[string]any{"id": float64(4)}},
+						},
+						"pageInfo": map[string]any{
+							"hasNextPage":     true,
+							"hasPreviousPage": true,
+							"startCursor":     artifactCursor(2, 3),
+							"endCursor":       artifactCursor(3, 4),
+						},
+					},
+				},
+			},
+			rateLimitExtension(1, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				if msg := errs.Error(); msg != "" {
+					t.Errorf("errors:\n%s", msg)
+				}
+			},
+		},
+		{
+			"artifacts with page and cursor",
+			nil,
+			&graphql.RawParams{
+				Query:     artifactsQuery,
+				Variables: map[string]any{"owner": "test-org", "name": "test-repo", "page": 2, "after": artifactCursor(1, 2)},
+			},
+			map[string]any{"test__repository": nil},
+			rateLimitExtension(0, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				if msg := errs.Error(); msg != fmt.Sprintf("input: test__repository.artifacts %s\n", resolvers.ErrPageWithCursor) {
+					t.Errorf("errors:\n%s", msg)
+				}
+			},
+		},
+		{
+			"artifacts with invalid cursor",
+			nil,
+			&graphql.RawParams{
+				Query:     artifactsQuery,
+				Variables: map[string]any{"owner": "test-org", "name": "test-repo", "after": "invalid"},
+			},
+			map[string]any{"test__repository": nil},
+			rateLimitExtension(0, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				if msg := errs.Error(); msg != fmt.Sprintf("input: test__repository.artifacts %s: %q\n", resolvers.ErrInvalidCursor, "invalid") {
+					t.Errorf("errors:\n%s", msg)
+				}
+			},
+		},
+		{
+			"entities",
+			[]*githubtest.Route{
+				{
+					Path:      "/repos/test-org/repo-a/actions/artifacts",
+					Responses: []*githubtest.Response{{Body: &github.ArtifactList{TotalCount: github.Int64(3), Artifacts: newArtifacts(0, 3, 1)}}},
+				},
+				{
+					Path:      "/repos/test-org/repo-b/actions/artifacts",
+					Responses: []*githubtest.Response{{Body: &github.ArtifactList{TotalCount: github.Int64(5), Artifacts: newArtifacts(0, 5, 1)}}},
+				},
+			},
+			&graphql.RawParams{
+				Query: entitiesQuery,
+				Variables: map[string]any{"representations": []any{
+					map[string]any{"__typename": "Repository", "nameWithOwner": "test-org/repo-a"},
+					map[string]any{"__typename": "Organization", "login": "test-org"},
+					map[string]any{"__typename": "Repository", "nameWithOwner": "test-org/repo-b"},
+					map[string]any{"__typename": "Repository", "nameWithOwner": "test-org/repo-a"},
+				}},
+			},
+			map[string]any{"_entities": []any{
+				map[string]any{"nameWithOwner": "test-org/repo-a", "artifacts": map[string]any{"totalCount": float64(3)}},
+				map[string]any{"login": "test-org"},
+				map[string]any{"nameWithOwner": "test-org/repo-b", "artifacts": map[string]any{"totalCount": float64(5)}},
+				map[string]any{"nameWithOwner": "test-org/repo-a", "artifacts": map[string]any{"totalCount": float64(3)}},
+			}},
+			// repo-a is fetched once for both of its representations
+			rateLimitExtension(2, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				if msg := errs.Error(); msg != "" {
+					t.Errorf("errors:\n%s", msg)
+				}
+			},
+		},
+		{
+			"entities with malformed keys",
+			[]*githubtest.Route{
+				{
+					Path:      "/repos/test-org/repo-a/actions/artifacts",
+					Responses: []*githubtest.Response{{Body: &github.ArtifactList{TotalCount: github.Int64(3), Artifacts: newArtifacts(0, 3, 1)}}},
+				},
+			},
+			&graphql.RawParams{
+				Query: entitiesQuery,
+				Variables: map[string]any{"representations": []any{
+					map[string]any{"__typename": "Repository", "nameWithOwner": "no-slash"},
+					map[string]any{"__typename": "Repository", "nameWithOwner": "test-org/repo-a"},
+					map[string]any{"__typename": "Organization", "login": ""},
+				}},
+			},
+			map[string]any{"_entities": []any{
+				nil,
+				map[string]any{"nameWithOwner": "test-org/repo-a", "artifacts": map[string]any{"totalCount": float64(3)}},
+				nil,
+			}},
+			rateLimitExtension(1, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				var got []string
+				for _, err := range errs {
+					got = append(got, fmt.Sprintf("%s: %s", err.Path, err.Message))
+				}
+				sort.Strings(got)
+				want := []string{
+					fmt.Sprintf("_entities[0]: %s: %q", resolvers.ErrInvalidNameWithOwner, "no-slash"),
+					fmt.Sprintf("_entities[2]: %s", resolvers.ErrEmptyLogin),
+				}
+				if diff := cmp.Diff(got, want); diff != "" {
+					t.Errorf("errors (-got, +want):\n%s", diff)
+				}
+			},
+		},
+		{
+			"entities without key",
+			[]*githubtest.Route{
+				{
+					Path:      "/repos/test-org/repo-a/actions/artifacts",
+					Responses: []*githubtest.Response{{Body: &github.ArtifactList{TotalCount: github.Int64(3), Artifacts: newArtifacts(0, 3, 1)}}},
+				},
+			},
+			&graphql.RawParams{
+				Query: entitiesQuery,
+				Variables: map[string]any{"representations": []any{
+					map[string]any{"__typename": "Repository", "nameWithOwner": "test-org/repo-a"},
+					map[string]any{"__typename": "Repository", "nameWithOwner": 1},
+					map[string]any{"__typename": "Organization"},
+					map[string]any{"__typename": "Organization", "login": "test-org"},
+				}},
+			},
+			map[string]any{"_entities": []any{
+				map[string]any{"nameWithOwner": "test-org/repo-a", "artifacts": map[string]any{"totalCount": float64(3)}},
+				nil,
+				nil,
+				map[string]any{"login": "test-org"},
+			}},
+			rateLimitExtension(1, 0),
+			func(t *testing.T, errs gqlerror.List) {
+				t.Helper()
+				var got []string
+				for _, err := range errs {
+					got = append(got, fmt.Sprintf("%s: %s", err.Path, err.Message))
+				}
+				sort.Strings(got)
+				want := []string{
+					fmt.Sprintf("_entities[1]: %s: nameWithOwner", resolvers.ErrInvalidEntityKey),
+					fmt.Sprintf("_entities[2]: %s: login", resolvers.ErrInvalidEntityKey),
+				}
+				if diff := cmp.Diff(got, want); diff != "" {
+					t.Errorf("errors (-got, +want):\n%s", diff)
+				}
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			githubClient, finite, err := newMockedGitHubClient(tc.responseDefinition)
-			if err != nil {
-				t.Fatal(err)
-			}
-			defer finite()
+			githubSrv := githubtest.NewServer(t, tc.routes...)
+			githubClient := githubSrv.Client(func(rt http.RoundTripper) http.RoundTripper { return &ratelimit.Transport{Base: rt} })
 			resp, err, close := sendGraphqlRequest(context.Background(), tc.graphqlParams, githubClient)
 			defer close()
 			if err != nil {
@@ -151,73 +496,57 @@ func TestHandler(t *testing.T) {
 			if diff := cmp.Diff(gqlResp.Extensions, tc.wantExtension); diff != "" {
 				t.Errorf("extension (-got, +want):\n%s", diff)
 			}
+			for _, route := range tc.routes {
+				if route.Calls() == 0 {
+					t.Errorf("%s is not called", route.Path)
+				}
+			}
 		})
 	}
 }
 
-func newMockedGitHubClient(h http.Handler) (*github.Client, func(), error) {
-	srv := httptest.NewServer(h)
-	client, err := github.NewEnterpriseClient(srv.URL, srv.URL, srv.Client())
-	if err != nil {
-		return nil, func() {}, err
-	}
-	return client, func() { srv.Close() }, nil
-}
-
-type mockAPIResponse struct {
-	method  string
-	urlPath string
-	code    int
-	body    any
-}
-
-var _ http.Handler = (*mockAPIResponse)(nil)
-
-func (a *mockAPIResponse) match(r *http.Request) bool {
-	method := a.method
-	if method == "" {
-		method = http.MethodGet
-	}
-	return fmt.Sprintf("%s %s", r.Method, r.URL.Path) == fmt.Sprintf("%s %s", method, a.urlPath)
-}
-
-func (a *mockAPIResponse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	b, err := json.Marshal(a.body)
-	if err != nil {
-		w.Header().Add("content-type", "application/json")
-		w.WriteHeader(599)
-		fmt.Fprintln(w, `{"error":"cannot encode body as a JSON"}`)
-		return
-	}
-	code := a.code
-	if code == 0 {
-		code = http.StatusOK
-	}
-	w.Header().Add("content-type", "application/json")
-	w.WriteHeader(code)
-	fmt.Fprintln(w, string(b))
-}
-
-type mockAPIResponseList []*mockAPIResponse
-
-var _ http.Handler = (mockAPIResponseList)(nil)
-
-func (l mockAPIResponseList) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	for _, def := range l {
-		if def.match(r) {
-			def.ServeHTTP(w, r)
-			return
+func TestHandler_replay(t *testing.T) {
+	githubSrv := githubtest.NewServer(t,
+		githubtest.GET("/orgs/{org}", githubtest.JSON(http.StatusOK, &github.Organization{
+			Login: github.String("aereal-test"),
+			Plan:  &github.Plan{Name: github.String("team"), Seats: github.Int(5), FilledSeats: github.Int(3)},
+		})),
+	)
+	cassette := filepath.Join(t.TempDir(), "cassettes", "organization_plan.json")
+	sendQuery := func(t *testing.T, mode githubtest.RecorderMode) {
+		githubClient := githubSrv.Client(func(rt http.RoundTripper) http.RoundTripper {
+			return &ratelimit.Transport{Base: githubtest.NewRecorder(t, cassette, githubtest.WithRecorderMode(mode), githubtest.WithRecorderBase(rt))}
+		})
+		resp, err, close := sendGraphqlRequest(context.Background(), &graphql.RawParams{Query: query, OperationName: "myQuery", Variables: map[string]any{"org": "aereal-test"}}, githubClient)
+		defer close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var gqlResp graphql.Response
+		if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
+			t.Fatalf("cannot decode to GraphQL response: %+v", err)
+		}
+		if len(gqlResp.Errors) > 0 {
+			t.Errorf("errors: %v", gqlResp.Errors)
+		}
+		var gotData any
+		if err := json.Unmarshal(gqlResp.Data, &gotData); err != nil {
+			t.Fatal(err)
+		}
+		wantData := map[string]any{"test__organization": map[string]any{"plan": map[string]any{"name": "team", "seats": float64(5), "filledSeats": float64(3)}}}
+		if diff := cmp.Diff(gotData, wantData); diff != "" {
+			t.Errorf("data (-got, +want):\n%s", diff)
 		}
 	}
-	noMatchingDefinitionFoundHandler(w, r)
+	// the cassette is written when the record subtest finishes
+	t.Run("record", func(t *testing.T) { sendQuery(t, githubtest.ModeRecord) })
+	t.Run("replay", func(t *testing.T) { sendQuery(t, githubtest.ModeReplay) })
+	if got := len(githubSrv.Requests()); got != 1 {
+		t.Errorf("replay must not call the upstream: got %d requests", got)
+	}
 }
 
-var noMatchingDefinitionFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("content-type", "application/json")
-	w.WriteHeader(599)
-	fmt.Fprintf(w, `{"error":"no matching definition found"}`)
-})
-
 func sendGraphqlRequest(ctx context.Context, params *graphql.RawParams, githubClient *github.Client) (*http.Response, error, func()) {
 	handlerSrv := httptest.NewServer(newHTTPHandler(githubClient))
 	close := func() { handlerSrv.Close() }
@@ -244,5 +573,55 @@ func newHTTPHandler(githubClient *github.Client) http.Handler {
 	h.AddTransport(transport.GET{})
 	h.AddTransport(transport.POST{})
 	h.Use(extension.Introspection{})
+	h.Use(ratelimit.Extension{})
+	h.Use(resolvers.Extension{})
+	h.SetErrorPresenter(resolvers.ErrorPresenter)
 	return h
 }
+
+func newArtifacts(offset, n int, size int64) []*github.Artifact {
+	artifacts := make([]*github.Artifact, n)
+	for i := range artifacts {
+		artifacts[i] = &github.Artifact{ID: github.Int64(int64(offset + i)), SizeInBytes: github.Int64(size)}
+	}
+	return artifacts
+}
+
+func artifactCursor(offset int, id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("artifact:%d:%d", offset, id)))
+}
+
+func rateLimitHeader(remaining int) http.Header {
+	return http.Header{
+		"X-Ratelimit-Limit":     {"5000"},
+		"X-Ratelimit-Remaining": {fmt.Sprint(remaining)},
+		"X-Ratelimit-Reset":     {"1660000000"},
+		"X-Ratelimit-Resource":  {"core"},
+	}
+}
+
+func rateLimitExtension(restCalls, cachedRestCalls int, rates ...*ratelimit.Rate) map[string]any {
+	resources := map[string]any{}
+	for _, rate := range rates {
+		resources[rate.Resource] = map[string]any{
+			"limit":     float64(rate.Limit),
+			"remaining": float64(rate.Remaining),
+			"reset":     rate.Reset.UTC().Format(time.RFC3339),
+		}
+	}
+	return map[string]any{ratelimit.ExtensionKey: map[string]any{
+		"resources":       resources,
+		"restCalls":       float64(restCalls),
+		"cachedRestCalls": float64(cachedRestCalls),
+	}}
+}
+
+func assertErrorExtensions(t *testing.T, errs gqlerror.List, want map[string]any) {
+	t.Helper()
+	if len(errs) != 1 {
+		t.Fatalf("errors: got=%d want=1:\n%s", len(errs), errs.Error())
+	}
+	if diff := cmp.Diff(errs[0].Extensions, want); diff != "" {
+		t.Errorf("error extensions (-got, +want):\n%s", diff)
+	}
+}
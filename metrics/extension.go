@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/aereal/github-graphql-proxy/telemetry"
+)
+
+// Extension observes the count and the latency of operations and resolvers.
+type Extension struct {
+	Metrics *Metrics
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "PrometheusMetrics"
+}
+
+func (Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	startedAt := time.Now()
+	resp := next(ctx)
+	name := e.Metrics.operationLabel(telemetry.OperationName(graphql.GetOperationContext(ctx)))
+	status := "ok"
+	if resp == nil || len(resp.Errors) > 0 {
+		status = "error"
+	}
+	e.Metrics.operations.WithLabelValues(name, status).Inc()
+	e.Metrics.operationDuration.WithLabelValues(name).Observe(time.Since(startedAt).Seconds())
+	return resp
+}
+
+func (e Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	startedAt := time.Now()
+	defer func() {
+		e.Metrics.resolverDuration.WithLabelValues(fc.Object + "." + fc.Field.Name).Observe(time.Since(startedAt).Seconds())
+	}()
+	return next(ctx)
+}
@@ -0,0 +1,185 @@
+// Package metrics exposes Prometheus metrics of GraphQL operations, resolvers and upstream REST calls.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/ratelimit"
+	"github.com/aereal/github-graphql-proxy/telemetry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "github_graphql_proxy"
+
+const (
+	// MaxOperationNames is the number of distinct operation names that have their own series.
+	// Operations named otherwise are counted as OtherOperation because the names are chosen by the clients.
+	MaxOperationNames = 100
+	// AnonymousOperation is the operation label of the operations without a name.
+	AnonymousOperation = telemetry.AnonymousOperation
+	// OtherOperation is the operation label of the operations beyond MaxOperationNames.
+	OtherOperation = "other"
+
+	// rateLimitSeriesTTL is how long the rate limit of a token is exposed after it is observed last.
+	// GitHub resets the rate limit hourly, so the older value means nothing.
+	rateLimitSeriesTTL = time.Hour
+	// rateLimitPruneInterval is the minimum interval between the pruning of the stale rate limit series.
+	rateLimitPruneInterval = time.Minute
+)
+
+type rateLimitSeries struct {
+	fingerprint string
+	resource    string
+}
+
+// Metrics holds the collectors and the registry they belong to.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	operations         *prometheus.CounterVec
+	operationDuration  *prometheus.HistogramVec
+	resolverDuration   *prometheus.HistogramVec
+	upstreamRequests   *prometheus.CounterVec
+	upstreamDuration   *prometheus.HistogramVec
+	rateLimitRemaining *prometheus.GaugeVec
+
+	mux            sync.Mutex
+	now            func() time.Time
+	operationNames map[string]struct{}
+	rateLimitSeen  map[rateLimitSeries]time.Time
+	lastPruned     time.Time
+}
+
+// New returns Metrics registered to a new registry along with the Go runtime and process collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry:       prometheus.NewRegistry(),
+		now:            time.Now,
+		operationNames: map[string]struct{}{},
+		rateLimitSeen:  map[rateLimitSeries]time.Time{},
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "graphql_operations_total",
+			Help:      "The number of GraphQL operations.",
+		}, []string{"operation", "status"}),
+		operationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_operation_duration_seconds",
+			Help:      "The latency of GraphQL operations.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation"}),
+		resolverDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "graphql_resolver_duration_seconds",
+			Help:      "The latency of GraphQL resolvers.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"field"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_requests_total",
+			Help:      "The number of GitHub REST API calls.",
+		}, []string{"method", "endpoint", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "The latency of GitHub REST API calls.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "endpoint", "status"}),
+		rateLimitRemaining: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "upstream_rate_limit_remaining",
+			Help:      "The last observed remaining GitHub REST API rate limit. Tokens not seen for an hour are dropped.",
+		}, []string{"token_fingerprint", "resource"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.operations,
+		m.operationDuration,
+		m.resolverDuration,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		m.rateLimitRemaining,
+	)
+	return m
+}
+
+// Register registers an additional collector.
+func (m *Metrics) Register(c prometheus.Collector) error {
+	return m.registry.Register(c)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// operationLabel returns the label of the operation name bounding the number of distinct labels by MaxOperationNames.
+func (m *Metrics) operationLabel(name string) string {
+	if name == "" {
+		return AnonymousOperation
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if _, ok := m.operationNames[name]; ok {
+		return name
+	}
+	if len(m.operationNames) >= MaxOperationNames {
+		return OtherOperation
+	}
+	m.operationNames[name] = struct{}{}
+	return name
+}
+
+// observeRateLimit sets the remaining rate limit of the token and drops the series of the tokens not seen for rateLimitSeriesTTL.
+func (m *Metrics) observeRateLimit(fingerprint string, rate *ratelimit.Rate) {
+	m.rateLimitRemaining.WithLabelValues(fingerprint, rate.Resource).Set(float64(rate.Remaining))
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	now := m.now()
+	m.rateLimitSeen[rateLimitSeries{fingerprint: fingerprint, resource: rate.Resource}] = now
+	if now.Sub(m.lastPruned) < rateLimitPruneInterval {
+		return
+	}
+	m.lastPruned = now
+	for series, seenAt := range m.rateLimitSeen {
+		if now.Sub(seenAt) > rateLimitSeriesTTL {
+			m.rateLimitRemaining.DeleteLabelValues(series.fingerprint, series.resource)
+			delete(m.rateLimitSeen, series)
+		}
+	}
+}
+
+// Transport returns http.RoundTripper that observes upstream calls made with the token.
+func (m *Metrics) Transport(base http.RoundTripper, fingerprint string) http.RoundTripper {
+	return &transport{metrics: m, base: base, fingerprint: fingerprint}
+}
+
+type transport struct {
+	metrics     *Metrics
+	base        http.RoundTripper
+	fingerprint string
+}
+
+var _ http.RoundTripper = (*transport)(nil)
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	startedAt := time.Now()
+	resp, err := t.base.RoundTrip(r)
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+		if rate, ok := ratelimit.ParseRate(resp.Header); ok {
+			t.metrics.observeRateLimit(t.fingerprint, rate)
+		}
+	}
+	endpoint := telemetry.EndpointTemplate(r.URL.Path)
+	t.metrics.upstreamRequests.WithLabelValues(r.Method, endpoint, status).Inc()
+	t.metrics.upstreamDuration.WithLabelValues(r.Method, endpoint, status).Observe(time.Since(startedAt).Seconds())
+	return resp, err
+}
@@ -0,0 +1,68 @@
+package metrics_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/aereal/github-graphql-proxy/metrics"
+	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/google/go-github/v47/github"
+)
+
+func TestMetrics(t *testing.T) {
+	githubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("x-ratelimit-remaining", "4321")
+		w.Header().Set("x-ratelimit-resource", "core")
+		fmt.Fprint(w, `{"plan":{"name":"enterprise"}}`)
+	}))
+	defer githubSrv.Close()
+	m := metrics.New()
+	githubClient, err := github.NewEnterpriseClient(githubSrv.URL, githubSrv.URL, &http.Client{Transport: m.Transport(githubSrv.Client().Transport, "0123abcd")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := handler.New(githubgraphqlproxy.NewExecutableSchema(githubgraphqlproxy.Config{Resolvers: resolvers.New(githubClient)}))
+	h.AddTransport(transport.POST{})
+	h.Use(metrics.Extension{Metrics: m})
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader(`{"query":"query myQuery { test__organization(login: \"test-org\") { plan { name } } }"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("content-type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exposition := string(body)
+	for _, want := range []string{
+		`github_graphql_proxy_graphql_operations_total{operation="myQuery",status="ok"} 1`,
+		`github_graphql_proxy_graphql_operation_duration_seconds_count{operation="myQuery"} 1`,
+		`github_graphql_proxy_graphql_resolver_duration_seconds_count{field="Organization.plan"} 1`,
+		`github_graphql_proxy_upstream_requests_total{endpoint="/orgs/{org}",method="GET",status="200"} 1`,
+		`github_graphql_proxy_upstream_request_duration_seconds_count{endpoint="/orgs/{org}",method="GET",status="200"} 1`,
+		`github_graphql_proxy_upstream_rate_limit_remaining{resource="core",token_fingerprint="0123abcd"} 4321`,
+	} {
+		if !strings.Contains(exposition, want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, exposition)
+		}
+	}
+}
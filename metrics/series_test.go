@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/ratelimit"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics_operationLabel(t *testing.T) {
+	m := New()
+	for i := 0; i < MaxOperationNames; i++ {
+		name := fmt.Sprintf("op%d", i)
+		if got := m.operationLabel(name); got != name {
+			t.Fatalf("got=%q want=%q", got, name)
+		}
+	}
+	type testCase struct {
+		name string
+		want string
+	}
+	testCases := []testCase{
+		{"", AnonymousOperation},
+		{"op0", "op0"},
+		{"overflow", OtherOperation},
+	}
+	for _, tc := range testCases {
+		if got := m.operationLabel(tc.name); got != tc.want {
+			t.Errorf("operationLabel(%q): got=%q want=%q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestMetrics_observeRateLimit(t *testing.T) {
+	m := New()
+	now := time.Date(2022, time.September, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	m.observeRateLimit("stale", &ratelimit.Rate{Resource: "core", Remaining: 10})
+	m.observeRateLimit("fresh", &ratelimit.Rate{Resource: "core", Remaining: 20})
+	now = now.Add(rateLimitSeriesTTL - time.Minute)
+	m.observeRateLimit("fresh", &ratelimit.Rate{Resource: "core", Remaining: 19})
+	if got := testutil.CollectAndCount(m.rateLimitRemaining); got != 2 {
+		t.Fatalf("series before expiry: got=%d want=2", got)
+	}
+	now = now.Add(time.Minute * 2)
+	m.observeRateLimit("fresh", &ratelimit.Rate{Resource: "core", Remaining: 18})
+	if got := testutil.CollectAndCount(m.rateLimitRemaining); got != 1 {
+		t.Errorf("series after expiry: got=%d want=1", got)
+	}
+	if got := testutil.ToFloat64(m.rateLimitRemaining.WithLabelValues("fresh", "core")); got != 18 {
+		t.Errorf("remaining: got=%v want=18", got)
+	}
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// ExtensionKey is the key of the response extension that Extension reports.
+const ExtensionKey = "githubRateLimit"
+
+// Extension reports the stats of upstream calls made during an operation in the response extensions.
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "GitHubRateLimit"
+}
+
+func (Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	stats := &Stats{}
+	resp := next(WithStats(ctx, stats))
+	if resp == nil {
+		return resp
+	}
+	if resp.Extensions == nil {
+		resp.Extensions = map[string]interface{}{}
+	}
+	resp.Extensions[ExtensionKey] = newReport(stats)
+	return resp
+}
+
+type report struct {
+	Resources       map[string]*resourceReport `json:"resources"`
+	RESTCalls       int                        `json:"restCalls"`
+	CachedRESTCalls int                        `json:"cachedRestCalls"`
+}
+
+type resourceReport struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+func newReport(stats *Stats) *report {
+	r := &report{Resources: map[string]*resourceReport{}}
+	r.RESTCalls, r.CachedRESTCalls = stats.Calls()
+	for resource, rate := range stats.Lowest() {
+		r.Resources[resource] = &resourceReport{Limit: rate.Limit, Remaining: rate.Remaining, Reset: rate.Reset}
+	}
+	return r
+}
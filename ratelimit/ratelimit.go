@@ -0,0 +1,123 @@
+// Package ratelimit collects the GitHub REST API rate limit observed while executing a GraphQL operation.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CachedResponseHeader is set by caching transports on responses that are served from a cache.
+const CachedResponseHeader = "X-Proxy-Cache"
+
+// CacheStatusHit means the response is served from a cache without calling GitHub.
+const CacheStatusHit = "HIT"
+
+type ctxKey struct{}
+
+// WithStats returns a new context that records upstream responses to the stats.
+func WithStats(ctx context.Context, stats *Stats) context.Context {
+	return context.WithValue(ctx, ctxKey{}, stats)
+}
+
+// StatsFromContext returns the stats bound to the context.
+func StatsFromContext(ctx context.Context) (*Stats, bool) {
+	stats, ok := ctx.Value(ctxKey{}).(*Stats)
+	return stats, ok
+}
+
+// Rate is the rate limit reported by X-RateLimit-* response headers.
+type Rate struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+	Resource  string
+}
+
+// ParseRate reads the rate limit from the response headers.
+func ParseRate(header http.Header) (*Rate, bool) {
+	remaining, err := strconv.Atoi(header.Get("x-ratelimit-remaining"))
+	if err != nil {
+		return nil, false
+	}
+	rate := &Rate{Remaining: remaining, Resource: header.Get("x-ratelimit-resource")}
+	if limit, err := strconv.Atoi(header.Get("x-ratelimit-limit")); err == nil {
+		rate.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get("x-ratelimit-reset"), 10, 64); err == nil {
+		rate.Reset = time.Unix(reset, 0).UTC()
+	}
+	return rate, true
+}
+
+// Stats counts upstream calls made during an operation and keeps the lowest remaining rate limit of each resource.
+//
+// GitHub limits each resource such as core and search separately, so the rates of different resources are not compared.
+type Stats struct {
+	mux    sync.Mutex
+	calls  int
+	cached int
+	lowest map[string]*Rate
+}
+
+// Record accounts the upstream response. The response may be nil if the call failed.
+func (s *Stats) Record(resp *http.Response) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.calls++
+	if resp == nil {
+		return
+	}
+	if status := resp.Header.Get(CachedResponseHeader); status != "" {
+		s.cached++
+		if status == CacheStatusHit {
+			// the headers of fresh cached responses are as old as the cache entry
+			return
+		}
+	}
+	rate, ok := ParseRate(resp.Header)
+	if !ok {
+		return
+	}
+	if s.lowest == nil {
+		s.lowest = map[string]*Rate{}
+	}
+	if lowest, ok := s.lowest[rate.Resource]; !ok || rate.Remaining < lowest.Remaining {
+		s.lowest[rate.Resource] = rate
+	}
+}
+
+// Calls returns the number of upstream calls and how many of them are served from a cache.
+func (s *Stats) Calls() (calls int, cached int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.calls, s.cached
+}
+
+// Lowest returns the rate limit with the lowest remaining budget of each resource, keyed by the resource name.
+func (s *Stats) Lowest() map[string]Rate {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	rates := make(map[string]Rate, len(s.lowest))
+	for resource, rate := range s.lowest {
+		rates[resource] = *rate
+	}
+	return rates
+}
+
+// Transport records upstream responses to the stats bound to the request context.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(r)
+	if stats, ok := StatsFromContext(r.Context()); ok {
+		stats.Record(resp)
+	}
+	return resp, err
+}
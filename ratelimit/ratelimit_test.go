@@ -0,0 +1,33 @@
+package ratelimit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStats_Lowest(t *testing.T) {
+	stats := &Stats{}
+	for _, header := range []http.Header{
+		{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4000"}, "X-Ratelimit-Reset": {"1660000000"}, "X-Ratelimit-Resource": {"core"}},
+		{"X-Ratelimit-Limit": {"30"}, "X-Ratelimit-Remaining": {"10"}, "X-Ratelimit-Reset": {"1660000060"}, "X-Ratelimit-Resource": {"search"}},
+		{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"3999"}, "X-Ratelimit-Reset": {"1660000000"}, "X-Ratelimit-Resource": {"core"}},
+		{"X-Ratelimit-Limit": {"30"}, "X-Ratelimit-Remaining": {"20"}, "X-Ratelimit-Reset": {"1660000060"}, "X-Ratelimit-Resource": {"search"}},
+		{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Resource": {"core"}, CachedResponseHeader: {CacheStatusHit}},
+	} {
+		stats.Record(&http.Response{Header: header})
+	}
+	stats.Record(nil)
+	want := map[string]Rate{
+		"core":   {Limit: 5000, Remaining: 3999, Reset: time.Unix(1660000000, 0).UTC(), Resource: "core"},
+		"search": {Limit: 30, Remaining: 10, Reset: time.Unix(1660000060, 0).UTC(), Resource: "search"},
+	}
+	if diff := cmp.Diff(stats.Lowest(), want); diff != "" {
+		t.Errorf("(-got, +want):\n%s", diff)
+	}
+	if calls, cached := stats.Calls(); calls != 6 || cached != 1 {
+		t.Errorf("calls: got=(%d, %d) want=(6, 1)", calls, cached)
+	}
+}
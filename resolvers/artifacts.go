@@ -0,0 +1,224 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/google/go-github/v47/github"
+)
+
+const (
+	artifactsPerPage     = 100
+	artifactCursorPrefix = "artifact:"
+)
+
+// encodeArtifactCursor encodes the offset of the artifact along with its ID.
+//
+// The ID lets a later request find the artifact even if it has moved to another offset because newer artifacts were uploaded or older ones expired.
+func encodeArtifactCursor(offset int, id int64) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s%d:%d", artifactCursorPrefix, offset, id)))
+}
+
+// decodeArtifactCursor decodes the cursor into the offset and the ID of the artifact.
+// It also accepts the cursors without ID issued by the earlier versions; their ID is 0.
+func decodeArtifactCursor(cursor string) (offset int, id int64, err error) {
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	s := string(b)
+	if !strings.HasPrefix(s, artifactCursorPrefix) {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	offsetPart, idPart, hasID := strings.Cut(strings.TrimPrefix(s, artifactCursorPrefix), ":")
+	offset, err = strconv.Atoi(offsetPart)
+	if err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	if hasID {
+		id, err = strconv.ParseInt(idPart, 10, 64)
+		if err != nil || id < 1 {
+			return 0, 0, fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+		}
+	}
+	return offset, id, nil
+}
+
+// artifactPager fetches the artifacts of the repository page by page and remembers the pages already fetched.
+//
+// It is shared among the resolvers running concurrently, so the pages are fetched one at a time.
+type artifactPager struct {
+	client *github.Client
+	owner  string
+	name   string
+
+	mux        sync.Mutex
+	pages      map[int][]*github.Artifact
+	totalCount int
+}
+
+func newArtifactPager(client *github.Client, owner, name string) *artifactPager {
+	return &artifactPager{client: client, owner: owner, name: name, pages: map[int][]*github.Artifact{}, totalCount: -1}
+}
+
+func (p *artifactPager) fetchPage(ctx context.Context, page int) ([]*github.Artifact, error) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if artifacts, ok := p.pages[page]; ok {
+		return artifacts, nil
+	}
+	list, _, err := p.client.Actions.ListArtifacts(ctx, p.owner, p.name, &github.ListOptions{Page: page, PerPage: artifactsPerPage})
+	if err != nil {
+		return nil, fmt.Errorf("Actions.ListArtifacts: %w", err)
+	}
+	p.pages[page] = list.Artifacts
+	p.totalCount = int(list.GetTotalCount())
+	return list.Artifacts, nil
+}
+
+// total returns total_count reported by GitHub. If no page is fetched yet, it fetches the page that contains the given offset.
+func (p *artifactPager) total(ctx context.Context, offset int) (int, error) {
+	if total, ok := p.knownTotal(); ok {
+		return total, nil
+	}
+	if _, err := p.fetchPage(ctx, offset/artifactsPerPage+1); err != nil {
+		return 0, err
+	}
+	total, _ := p.knownTotal()
+	return total, nil
+}
+
+// knownTotal returns total_count reported by the pages fetched so far.
+func (p *artifactPager) knownTotal() (int, bool) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	return p.totalCount, p.totalCount >= 0
+}
+
+// locate returns the current offset of the artifact that the cursor points to.
+//
+// It looks for the artifact in the page of the offset recorded in the cursor and in its neighbouring pages,
+// and falls back to the recorded offset if the artifact is not found there, for example because it has been deleted.
+func (p *artifactPager) locate(ctx context.Context, offset int, id int64) (int, error) {
+	if id == 0 {
+		return offset, nil
+	}
+	page := offset/artifactsPerPage + 1
+	for _, candidate := range []int{page, page + 1, page - 1} {
+		if candidate < 1 {
+			continue
+		}
+		if total, ok := p.knownTotal(); ok && (candidate-1)*artifactsPerPage >= total {
+			continue
+		}
+		artifacts, err := p.fetchPage(ctx, candidate)
+		if err != nil {
+			return 0, err
+		}
+		for i, artifact := range artifacts {
+			if artifact.GetID() == id {
+				return (candidate-1)*artifactsPerPage + i, nil
+			}
+		}
+	}
+	return offset, nil
+}
+
+// slice returns the artifacts in the range of [start, end).
+func (p *artifactPager) slice(ctx context.Context, start, end int) ([]*github.Artifact, error) {
+	out := make([]*github.Artifact, 0, end-start)
+	for offset := start; offset < end; {
+		page := offset/artifactsPerPage + 1
+		artifacts, err := p.fetchPage(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		i := offset - (page-1)*artifactsPerPage
+		if i >= len(artifacts) {
+			break
+		}
+		n := end - offset
+		if rest := len(artifacts) - i; n > rest {
+			n = rest
+		}
+		out = append(out, artifacts[i:i+n]...)
+		offset += n
+	}
+	return out, nil
+}
+
+// totalSizeInBytes sums up the size of all artifacts fetching all the pages.
+func (p *artifactPager) totalSizeInBytes(ctx context.Context) (int64, error) {
+	total, err := p.total(ctx, 0)
+	if err != nil {
+		return 0, err
+	}
+	artifacts, err := p.slice(ctx, 0, total)
+	if err != nil {
+		return 0, err
+	}
+	var size int64
+	for _, artifact := range artifacts {
+		size += artifact.GetSizeInBytes()
+	}
+	return size, nil
+}
+
+// artifactWindow computes the range of offsets designated by the Relay connection arguments.
+// after and before are the offsets located from the cursors.
+func artifactWindow(total int, first *int, after *int, last *int, before *int) (start int, end int, err error) {
+	end = total
+	if after != nil {
+		start = *after + 1
+	}
+	if before != nil && *before < end {
+		end = *before
+	}
+	if start > end {
+		start = end
+	}
+	if first == nil && last == nil {
+		first = github.Int(githubgraphqlproxy.DefaultArtifactsPageSize)
+	}
+	if first != nil {
+		if *first < 0 {
+			return 0, 0, fmt.Errorf("%w: first=%d", ErrNegativePaginationArgument, *first)
+		}
+		if start+*first < end {
+			end = start + *first
+		}
+	}
+	if last != nil {
+		if *last < 0 {
+			return 0, 0, fmt.Errorf("%w: last=%d", ErrNegativePaginationArgument, *last)
+		}
+		if end-*last > start {
+			start = end - *last
+		}
+	}
+	return start, end, nil
+}
+
+// pageOffset converts the deprecated page argument into the offset that after designates.
+// The page is 1-based and has first items, as GitHub's page and per_page do.
+func pageOffset(page int, first *int, after *string, last *int, before *string) (*int, error) {
+	if after != nil || last != nil || before != nil {
+		return nil, ErrPageWithCursor
+	}
+	if page < 1 {
+		return nil, fmt.Errorf("%w: page=%d", ErrInvalidPage, page)
+	}
+	if page == 1 {
+		return nil, nil
+	}
+	size := githubgraphqlproxy.DefaultArtifactsPageSize
+	if first != nil {
+		size = *first
+	}
+	return github.Int((page-1)*size - 1), nil
+}
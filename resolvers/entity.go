@@ -0,0 +1,61 @@
+package resolvers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// addEntityError reports the error of the i-th representation of the type at its path such as ["_entities", 2].
+func addEntityError(ctx context.Context, typeName string, i int, err error) {
+	path := graphql.GetFieldContext(ctx).Path()
+	if idx, ok := representationIndex(ctx, typeName, i); ok {
+		path = append(path, ast.PathIndex(idx))
+	}
+	graphql.AddError(ctx, gqlerror.WrapPath(path, err))
+}
+
+// validateEntityKey returns ErrInvalidEntityKey if the key of the i-th representation of the type is missing or not a string.
+//
+// Such a key is replaced with the empty string by Extension, so the resolvers have to tell it from the empty string sent by the client.
+func validateEntityKey(ctx context.Context, typeName string, i int) error {
+	invalid, _ := ctx.Value(invalidKeysCtxKey{}).(map[int]bool)
+	if idx, ok := representationIndex(ctx, typeName, i); ok && invalid[idx] {
+		return fmt.Errorf("%w: %s", ErrInvalidEntityKey, entityKeys[typeName])
+	}
+	return nil
+}
+
+// representationIndex maps the i-th representation of the type to the index in all the representations,
+// because the entity resolvers receive the representations of their own type only.
+func representationIndex(ctx context.Context, typeName string, i int) (int, bool) {
+	reps, _ := graphql.GetFieldContext(ctx).Args["representations"].([]map[string]interface{})
+	for idx, rep := range reps {
+		if rep["__typename"] != typeName {
+			continue
+		}
+		if i == 0 {
+			return idx, true
+		}
+		i--
+	}
+	return 0, false
+}
+
+// parseNameWithOwner parses the key of Repository such as "aereal/github-graphql-proxy".
+func parseNameWithOwner(nameWithOwner string) (*githubgraphqlproxy.Repository, error) {
+	owner, name, ok := strings.Cut(nameWithOwner, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNameWithOwner, nameWithOwner)
+	}
+	return &githubgraphqlproxy.Repository{
+		Owner:         owner,
+		Name:          name,
+		NameWithOwner: nameWithOwner,
+	}, nil
+}
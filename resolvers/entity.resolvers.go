@@ -5,24 +5,56 @@ package resolvers
 
 import (
 	"context"
-	"strings"
 
 	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
 )
 
-// FindOrganizationByLogin is the resolver for the findOrganizationByLogin field.
-func (r *entityResolver) FindOrganizationByLogin(ctx context.Context, login string) (*githubgraphqlproxy.Organization, error) {
-	return &githubgraphqlproxy.Organization{Login: login}, nil
+// FindManyOrganizationByLogins is the resolver for the findManyOrganizationByLogins field.
+func (r *entityResolver) FindManyOrganizationByLogins(ctx context.Context, reps []*githubgraphqlproxy.OrganizationByLoginsInput) ([]*githubgraphqlproxy.Organization, error) {
+	orgs := make([]*githubgraphqlproxy.Organization, len(reps))
+	seen := map[string]*githubgraphqlproxy.Organization{}
+	for i, rep := range reps {
+		if err := validateEntityKey(ctx, "Organization", i); err != nil {
+			addEntityError(ctx, "Organization", i, err)
+			continue
+		}
+		if org, ok := seen[rep.Login]; ok {
+			orgs[i] = org
+			continue
+		}
+		if rep.Login == "" {
+			addEntityError(ctx, "Organization", i, ErrEmptyLogin)
+			continue
+		}
+		org := &githubgraphqlproxy.Organization{Login: rep.Login}
+		seen[rep.Login] = org
+		orgs[i] = org
+	}
+	return orgs, nil
 }
 
-// FindRepositoryByNameWithOwner is the resolver for the findRepositoryByNameWithOwner field.
-func (r *entityResolver) FindRepositoryByNameWithOwner(ctx context.Context, nameWithOwner string) (*githubgraphqlproxy.Repository, error) {
-	parts := strings.Split(nameWithOwner, "/")
-	return &githubgraphqlproxy.Repository{
-		Owner:         parts[0],
-		Name:          parts[1],
-		NameWithOwner: nameWithOwner,
-	}, nil
+// FindManyRepositoryByNameWithOwners is the resolver for the findManyRepositoryByNameWithOwners field.
+func (r *entityResolver) FindManyRepositoryByNameWithOwners(ctx context.Context, reps []*githubgraphqlproxy.RepositoryByNameWithOwnersInput) ([]*githubgraphqlproxy.Repository, error) {
+	repos := make([]*githubgraphqlproxy.Repository, len(reps))
+	seen := map[string]*githubgraphqlproxy.Repository{}
+	for i, rep := range reps {
+		if err := validateEntityKey(ctx, "Repository", i); err != nil {
+			addEntityError(ctx, "Repository", i, err)
+			continue
+		}
+		if repo, ok := seen[rep.NameWithOwner]; ok {
+			repos[i] = repo
+			continue
+		}
+		repo, err := parseNameWithOwner(rep.NameWithOwner)
+		if err != nil {
+			addEntityError(ctx, "Repository", i, err)
+			continue
+		}
+		seen[rep.NameWithOwner] = repo
+		repos[i] = repo
+	}
+	return repos, nil
 }
 
 // Entity returns githubgraphqlproxy.EntityResolver implementation.
@@ -3,5 +3,13 @@ package resolvers
 import "errors"
 
 var (
-	ErrOrganizationPlanIsNil = errors.New("organization.plan in the response from GitHub is nil")
+	ErrOrganizationPlanIsNil      = errors.New("organization.plan in the response from GitHub is nil")
+	ErrInvalidCursor              = errors.New("invalid cursor")
+	ErrNegativePaginationArgument = errors.New("pagination argument must not be negative")
+	ErrInvalidPage                = errors.New("page must be positive")
+	ErrPageWithCursor             = errors.New("page cannot be combined with after, last or before")
+	ErrInvalidNameWithOwner       = errors.New("nameWithOwner must be in the form of owner/name")
+	ErrEmptyLogin                 = errors.New("login must not be empty")
+	ErrInvalidEntityKey           = errors.New("the key of the entity representation must be a string")
+	ErrInstallationUnsupported    = errors.New("the field is not available to GitHub App installations because they do not belong to any user")
 )
@@ -0,0 +1,88 @@
+package resolvers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// entityKeys maps the entity types to their key fields.
+var entityKeys = map[string]string{
+	"Organization": "login",
+	"Repository":   "nameWithOwner",
+}
+
+type loadersCtxKey struct{}
+
+type invalidKeysCtxKey struct{}
+
+// Extension prepares each operation for the resolvers.
+//
+// It shares the artifact pagers among the resolvers of an operation so that each repository is fetched once.
+// It also lets the entity resolvers report the representations whose key is missing or not a string one by one:
+// otherwise the generated code fails all the representations of the type at ["_entities"].
+type Extension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "Resolvers"
+}
+
+func (Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	return next(context.WithValue(ctx, loadersCtxKey{}, &loaders{pagers: map[string]*artifactPager{}}))
+}
+
+func (Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Query" || fc.Field.Name != "_entities" {
+		return next(ctx)
+	}
+	reps, _ := fc.Args["representations"].([]map[string]interface{})
+	invalid := map[int]bool{}
+	for i, rep := range reps {
+		typeName, _ := rep["__typename"].(string)
+		key, ok := entityKeys[typeName]
+		if !ok {
+			continue
+		}
+		if _, ok := rep[key].(string); !ok {
+			// the empty key passes the generated code and the entity resolvers report it
+			rep[key] = ""
+			invalid[i] = true
+		}
+	}
+	return next(context.WithValue(ctx, invalidKeysCtxKey{}, invalid))
+}
+
+// loaders holds the loaders shared among the resolvers of an operation.
+type loaders struct {
+	mux    sync.Mutex
+	pagers map[string]*artifactPager
+}
+
+// artifactPager returns the pager of the repository shared in the operation, or a new one if Extension is not used.
+func (r *Resolver) artifactPager(ctx context.Context, owner, name string) *artifactPager {
+	l, ok := ctx.Value(loadersCtxKey{}).(*loaders)
+	if !ok {
+		return newArtifactPager(r.githubClient, owner, name)
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	key := owner + "/" + name
+	if p, ok := l.pagers[key]; ok {
+		return p
+	}
+	p := newArtifactPager(r.githubClient, owner, name)
+	l.pagers[key] = p
+	return p
+}
@@ -0,0 +1,164 @@
+package resolvers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/google/go-github/v47/github"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Error codes reported in extensions.code of GraphQL errors caused by GitHub or the policy.
+const (
+	CodeNotFound            = "NOT_FOUND"
+	CodeForbidden           = "FORBIDDEN"
+	CodeInsufficientScopes  = "INSUFFICIENT_SCOPES"
+	CodePolicyDenied        = "POLICY_DENIED"
+	CodeUnauthenticated     = "UNAUTHENTICATED"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamError       = "UPSTREAM_ERROR"
+	// CodeUnsupportedCredential means the field cannot be resolved as the GitHub App installation the proxy authenticates as.
+	CodeUnsupportedCredential = "UNSUPPORTED_CREDENTIAL"
+)
+
+// ErrorPresenter is graphql.ErrorPresenterFunc that classifies errors from GitHub into extensions.code.
+//
+// It also reports the status code and X-GitHub-Request-Id of the upstream response if any.
+// If the token lacks the scopes or the permissions the endpoint requires, the error names them.
+// Denials by authz.Policy are reported as CodePolicyDenied with the rule.
+// The fields that GitHub App installations cannot resolve are reported as CodeUnsupportedCredential.
+// If the organization rejects the token for SAML SSO, the error tells the URL to authorize the token.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if errors.Is(err, ErrInstallationUnsupported) || errors.Is(err, authz.ErrNoInstallationTarget) {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = map[string]interface{}{}
+		}
+		gqlErr.Extensions["code"] = CodeUnsupportedCredential
+		return gqlErr
+	}
+	var policyErr *authz.PolicyError
+	if errors.As(err, &policyErr) {
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = map[string]interface{}{}
+		}
+		gqlErr.Extensions["code"] = CodePolicyDenied
+		gqlErr.Extensions["policyRule"] = policyErr.Rule
+		return gqlErr
+	}
+	code, resp, ok := classifyUpstreamError(err)
+	if !ok {
+		return gqlErr
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]interface{}{}
+	}
+	gqlErr.Extensions["code"] = code
+	if code == CodeForbidden || code == CodeNotFound {
+		presentMissingPermissions(gqlErr, resp, authz.TokenTypeFromContext(ctx))
+	}
+	if code == CodeForbidden && resp != nil {
+		if url := authz.ParseCredential(resp.Header).SSOAuthorizationURL; url != "" {
+			gqlErr.Extensions["ssoAuthorizationUrl"] = url
+		}
+	}
+	if resp != nil {
+		gqlErr.Extensions["upstreamStatus"] = resp.StatusCode
+		if requestID := resp.Header.Get("x-github-request-id"); requestID != "" {
+			gqlErr.Extensions["githubRequestId"] = requestID
+		}
+	}
+	return gqlErr
+}
+
+// presentMissingPermissions turns the error into CodeInsufficientScopes if the classic token lacks the scopes the endpoint requires.
+//
+// GitHub answers 404 instead of 403 to some endpoints such as billing, so the status code alone cannot tell it.
+// GitHub does not tell the permissions granted to the other tokens, so their errors keep the code and only hint requiredPermissions.
+func presentMissingPermissions(gqlErr *gqlerror.Error, resp *http.Response, tokenType authz.TokenType) {
+	if resp == nil {
+		return
+	}
+	cred := authz.ParseCredential(resp.Header)
+	if !tokenType.Scoped() || !cred.MissingScopes() {
+		if len(cred.AcceptedPermissions) > 0 {
+			gqlErr.Extensions["requiredPermissions"] = cred.AcceptedPermissions
+		}
+		return
+	}
+	gqlErr.Extensions["code"] = CodeInsufficientScopes
+	var required []string
+	if len(cred.AcceptedScopes) > 0 {
+		gqlErr.Extensions["requiredScopes"] = cred.AcceptedScopes
+		gqlErr.Extensions["grantedScopes"] = nonNil(cred.GrantedScopes)
+		granted := strings.Join(cred.GrantedScopes, ", ")
+		if granted == "" {
+			granted = "none"
+		}
+		required = append(required, fmt.Sprintf("one of the scopes %s (granted: %s)", strings.Join(cred.AcceptedScopes, ", "), granted))
+	}
+	if len(cred.AcceptedPermissions) > 0 {
+		gqlErr.Extensions["requiredPermissions"] = cred.AcceptedPermissions
+		required = append(required, fmt.Sprintf("the permissions %s", strings.Join(cred.AcceptedPermissions, ", ")))
+	}
+	gqlErr.Message = fmt.Sprintf("%s: the token requires %s", gqlErr.Message, strings.Join(required, " or "))
+}
+
+func nonNil(xs []string) []string {
+	if xs == nil {
+		return []string{}
+	}
+	return xs
+}
+
+func classifyUpstreamError(err error) (string, *http.Response, bool) {
+	var (
+		rateLimitErr      *github.RateLimitError
+		abuseRateLimitErr *github.AbuseRateLimitError
+		errResp           *github.ErrorResponse
+		urlErr            *url.Error
+	)
+	switch {
+	case errors.As(err, &rateLimitErr):
+		return CodeRateLimited, rateLimitErr.Response, true
+	case errors.As(err, &abuseRateLimitErr):
+		return CodeRateLimited, abuseRateLimitErr.Response, true
+	case errors.As(err, &errResp):
+		return codeOfStatus(errResp.Response), errResp.Response, true
+	case errors.As(err, &urlErr):
+		var netErr net.Error
+		if errors.As(urlErr.Err, &netErr) || errors.Is(urlErr.Err, io.EOF) || errors.Is(urlErr.Err, io.ErrUnexpectedEOF) {
+			return CodeUpstreamUnavailable, nil, true
+		}
+	}
+	return "", nil, false
+}
+
+func codeOfStatus(resp *http.Response) string {
+	if resp == nil {
+		return CodeUpstreamError
+	}
+	switch status := resp.StatusCode; {
+	case status == http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case status == http.StatusForbidden:
+		return CodeForbidden
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status >= http.StatusInternalServerError:
+		return CodeUpstreamUnavailable
+	default:
+		return CodeUpstreamError
+	}
+}
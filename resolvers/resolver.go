@@ -3,13 +3,26 @@
 package resolvers
 
 import (
+	"github.com/aereal/github-graphql-proxy/authz"
 	"github.com/google/go-github/v47/github"
 )
 
-func New(githubClient *github.Client) *Resolver {
-	return &Resolver{githubClient: githubClient}
+type Option func(*Resolver)
+
+// WithPolicy makes the resolvers ask the policy before calling GitHub.
+func WithPolicy(policy *authz.Policy) Option {
+	return func(r *Resolver) { r.policy = policy }
+}
+
+func New(githubClient *github.Client, opts ...Option) *Resolver {
+	r := &Resolver{githubClient: githubClient}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 type Resolver struct {
 	githubClient *github.Client
+	policy       *authz.Policy
 }
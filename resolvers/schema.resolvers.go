@@ -8,11 +8,15 @@ import (
 	"fmt"
 
 	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/aereal/github-graphql-proxy/authz"
 	"github.com/google/go-github/v47/github"
 )
 
 // Plan is the resolver for the plan field.
 func (r *organizationResolver) Plan(ctx context.Context, obj *githubgraphqlproxy.Organization) (*githubgraphqlproxy.Plan, error) {
+	if err := r.policy.AuthorizeOrganization(obj.Login); err != nil {
+		return nil, err
+	}
 	org, _, err := r.githubClient.Organizations.Get(ctx, obj.Login)
 	if err != nil {
 		return nil, fmt.Errorf("Organizations.Get: %w", err)
@@ -32,6 +36,9 @@ func (r *organizationResolver) Plan(ctx context.Context, obj *githubgraphqlproxy
 
 // Actions is the resolver for the actions field.
 func (r *organizationBillingResolver) Actions(ctx context.Context, obj *githubgraphqlproxy.OrganizationBilling) (*githubgraphqlproxy.ActionBilling, error) {
+	if err := r.policy.AuthorizeBilling(ctx, r.githubClient, obj.OrganizationLogin); err != nil {
+		return nil, err
+	}
 	billing, _, err := r.githubClient.Billing.GetActionsBillingOrg(ctx, obj.OrganizationLogin)
 	if err != nil {
 		return nil, fmt.Errorf("Billing.GetActionsBillingOrg: %w", err)
@@ -56,6 +63,9 @@ func (r *organizationBillingResolver) Actions(ctx context.Context, obj *githubgr
 
 // Storage is the resolver for the storage field.
 func (r *organizationBillingResolver) Storage(ctx context.Context, obj *githubgraphqlproxy.OrganizationBilling) (*githubgraphqlproxy.StorageBilling, error) {
+	if err := r.policy.AuthorizeBilling(ctx, r.githubClient, obj.OrganizationLogin); err != nil {
+		return nil, err
+	}
 	billing, _, err := r.githubClient.Billing.GetStorageBillingOrg(ctx, obj.OrganizationLogin)
 	if err != nil {
 		return nil, fmt.Errorf("Billing.GetStorageBillingOrg: %w", err)
@@ -77,28 +87,99 @@ func (r *queryResolver) TestRepository(ctx context.Context, owner string, name s
 	return &githubgraphqlproxy.Repository{Owner: owner, Name: name}, nil
 }
 
+// ViewerCredential is the resolver for the viewerCredential field.
+func (r *queryResolver) ViewerCredential(ctx context.Context) (*githubgraphqlproxy.ViewerCredential, error) {
+	if authz.TokenTypeFromContext(ctx) == authz.TokenTypeInstallation {
+		return nil, ErrInstallationUnsupported
+	}
+	// GET /rate_limit does not count against the rate limit and answers the credential headers as other endpoints do
+	_, resp, err := r.githubClient.RateLimits(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("RateLimits: %w", err)
+	}
+	cred := authz.ParseCredential(resp.Header)
+	return &githubgraphqlproxy.ViewerCredential{
+		TokenType: authz.TokenTypeFromContext(ctx),
+		Scopes:    nonNil(cred.GrantedScopes),
+		ExpiresAt: cred.ExpiresAt,
+	}, nil
+}
+
 // Artifacts is the resolver for the artifacts field.
-func (r *repositoryResolver) Artifacts(ctx context.Context, obj *githubgraphqlproxy.Repository, first *int, page *int) (*githubgraphqlproxy.RepositoryArtifactConnection, error) {
-	listOpts := &github.ListOptions{}
-	if first != nil {
-		listOpts.PerPage = *first
+func (r *repositoryResolver) Artifacts(ctx context.Context, obj *githubgraphqlproxy.Repository, first *int, after *string, last *int, before *string, page *int) (*githubgraphqlproxy.RepositoryArtifactConnection, error) {
+	if err := r.policy.AuthorizeArtifacts(obj.Owner, obj.Name); err != nil {
+		return nil, err
 	}
+	pager := r.artifactPager(ctx, obj.Owner, obj.Name)
+	var afterOffset, beforeOffset *int
+	var afterID, beforeID int64
 	if page != nil {
-		listOpts.Page = *page
+		offset, err := pageOffset(*page, first, after, last, before)
+		if err != nil {
+			return nil, err
+		}
+		afterOffset = offset
+	}
+	if after != nil {
+		offset, id, err := decodeArtifactCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		afterOffset, afterID = &offset, id
+	}
+	if before != nil {
+		offset, id, err := decodeArtifactCursor(*before)
+		if err != nil {
+			return nil, err
+		}
+		beforeOffset, beforeID = &offset, id
+	}
+	var startHint int
+	if afterOffset != nil {
+		startHint = *afterOffset + 1
+	}
+	total, err := pager.total(ctx, startHint)
+	if err != nil {
+		return nil, err
+	}
+	if afterOffset != nil {
+		offset, err := pager.locate(ctx, *afterOffset, afterID)
+		if err != nil {
+			return nil, err
+		}
+		afterOffset = &offset
+	}
+	if beforeOffset != nil {
+		offset, err := pager.locate(ctx, *beforeOffset, beforeID)
+		if err != nil {
+			return nil, err
+		}
+		beforeOffset = &offset
 	}
-	artifacts, _, err := r.githubClient.Actions.ListArtifacts(ctx, obj.Owner, obj.Name, listOpts)
+	start, end, err := artifactWindow(total, first, afterOffset, last, beforeOffset)
 	if err != nil {
-		return nil, fmt.Errorf("Actions.ListArtifacts: %w", err)
+		return nil, err
 	}
-	out := &githubgraphqlproxy.RepositoryArtifactConnection{Nodes: make([]*githubgraphqlproxy.Artifact, len(artifacts.Artifacts))}
-	out.TotalCount = len(artifacts.Artifacts)
-	for i, artifact := range artifacts.Artifacts {
-		size := artifact.GetSizeInBytes()
-		out.TotalSizeInBytes += size
+	artifacts, err := pager.slice(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	out := &githubgraphqlproxy.RepositoryArtifactConnection{
+		Owner:      obj.Owner,
+		Name:       obj.Name,
+		TotalCount: total,
+		Edges:      make([]*githubgraphqlproxy.RepositoryArtifactEdge, len(artifacts)),
+		Nodes:      make([]*githubgraphqlproxy.Artifact, len(artifacts)),
+		PageInfo: &githubgraphqlproxy.PageInfo{
+			HasPreviousPage: start > 0,
+			HasNextPage:     start+len(artifacts) < total,
+		},
+	}
+	for i, artifact := range artifacts {
 		a := &githubgraphqlproxy.Artifact{
 			ID:                 int(artifact.GetID()),
 			Name:               artifact.GetName(),
-			SizeInBytes:        int(size),
+			SizeInBytes:        int(artifact.GetSizeInBytes()),
 			ArchiveDownloadURL: artifact.GetArchiveDownloadURL(),
 			Expired:            artifact.GetExpired(),
 		}
@@ -109,10 +190,53 @@ func (r *repositoryResolver) Artifacts(ctx context.Context, obj *githubgraphqlpr
 			a.ExpiresAt = artifact.ExpiresAt.Time
 		}
 		out.Nodes[i] = a
+		out.Edges[i] = &githubgraphqlproxy.RepositoryArtifactEdge{Cursor: encodeArtifactCursor(start+i, artifact.GetID()), Node: a}
+	}
+	if len(out.Edges) > 0 {
+		out.PageInfo.StartCursor = &out.Edges[0].Cursor
+		out.PageInfo.EndCursor = &out.Edges[len(out.Edges)-1].Cursor
 	}
 	return out, nil
 }
 
+// TotalSizeInBytes is the resolver for the totalSizeInBytes field.
+func (r *repositoryArtifactConnectionResolver) TotalSizeInBytes(ctx context.Context, obj *githubgraphqlproxy.RepositoryArtifactConnection) (*int, error) {
+	size, err := r.artifactPager(ctx, obj.Owner, obj.Name).totalSizeInBytes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	n := int(size)
+	return &n, nil
+}
+
+// SsoAuthorization is the resolver for the ssoAuthorization field.
+func (r *viewerCredentialResolver) SsoAuthorization(ctx context.Context, obj *githubgraphqlproxy.ViewerCredential) (*githubgraphqlproxy.SSOAuthorization, error) {
+	// GitHub omits the organizations that enforce SAML SSO and have not authorized the token from the list,
+	// and tells their IDs in X-GitHub-SSO of each page
+	out := &githubgraphqlproxy.SSOAuthorization{AuthorizedOrganizations: []string{}, UnauthorizedOrganizationIDs: []int64{}}
+	seen := map[int64]bool{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		orgs, resp, err := r.githubClient.Organizations.List(ctx, "", opts)
+		if err != nil {
+			return nil, fmt.Errorf("Organizations.List: %w", err)
+		}
+		for _, org := range orgs {
+			out.AuthorizedOrganizations = append(out.AuthorizedOrganizations, org.GetLogin())
+		}
+		for _, id := range authz.ParseCredential(resp.Header).SSOUnauthorizedOrganizationIDs {
+			if !seen[id] {
+				seen[id] = true
+				out.UnauthorizedOrganizationIDs = append(out.UnauthorizedOrganizationIDs, id)
+			}
+		}
+		if resp.NextPage == 0 {
+			return out, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
 // Organization returns githubgraphqlproxy.OrganizationResolver implementation.
 func (r *Resolver) Organization() githubgraphqlproxy.OrganizationResolver {
 	return &organizationResolver{r}
@@ -129,7 +253,19 @@ func (r *Resolver) Query() githubgraphqlproxy.QueryResolver { return &queryResol
 // Repository returns githubgraphqlproxy.RepositoryResolver implementation.
 func (r *Resolver) Repository() githubgraphqlproxy.RepositoryResolver { return &repositoryResolver{r} }
 
+// RepositoryArtifactConnection returns githubgraphqlproxy.RepositoryArtifactConnectionResolver implementation.
+func (r *Resolver) RepositoryArtifactConnection() githubgraphqlproxy.RepositoryArtifactConnectionResolver {
+	return &repositoryArtifactConnectionResolver{r}
+}
+
+// ViewerCredential returns githubgraphqlproxy.ViewerCredentialResolver implementation.
+func (r *Resolver) ViewerCredential() githubgraphqlproxy.ViewerCredentialResolver {
+	return &viewerCredentialResolver{r}
+}
+
 type organizationResolver struct{ *Resolver }
 type organizationBillingResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
 type repositoryResolver struct{ *Resolver }
+type repositoryArtifactConnectionResolver struct{ *Resolver }
+type viewerCredentialResolver struct{ *Resolver }
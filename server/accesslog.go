@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/aereal/github-graphql-proxy/ratelimit"
+	"github.com/aereal/github-graphql-proxy/telemetry"
+)
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+var (
+	ErrUnknownLogFormat = errors.New("unknown log format")
+	ErrUnknownLogLevel  = errors.New("unknown log level")
+)
+
+// NewLogger returns a logger writes to w in the format at or above the level.
+func NewLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lv slog.Level
+	if err := lv.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLogLevel, level)
+	}
+	opts := &slog.HandlerOptions{Level: lv}
+	switch strings.ToLower(format) {
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case LogFormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLogFormat, format)
+	}
+}
+
+type accessLogCtxKey struct{}
+
+// accessLogEntry is filled by accessLogExtension while the operation is executed.
+type accessLogEntry struct {
+	mux           sync.Mutex
+	operation     string
+	errors        int
+	upstreamCalls int
+	cachedCalls   int
+}
+
+func withAccessLogEntry(ctx context.Context, entry *accessLogEntry) context.Context {
+	return context.WithValue(ctx, accessLogCtxKey{}, entry)
+}
+
+// accessLog logs each GraphQL request with the operation and the upstream calls it made.
+func accessLog(logger *slog.Logger, fingerprint string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedAt := time.Now()
+		entry := &accessLogEntry{}
+		sw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r.WithContext(withAccessLogEntry(r.Context(), entry)))
+		entry.mux.Lock()
+		defer entry.mux.Unlock()
+		logger.LogAttrs(r.Context(), slog.LevelInfo, "graphql request",
+			slog.String("operation", entry.operation),
+			slog.String("client_name", r.Header.Get("apollographql-client-name")),
+			slog.String("client_version", r.Header.Get("apollographql-client-version")),
+			slog.String("token_fingerprint", fingerprint),
+			slog.String("method", r.Method),
+			slog.Int("status", sw.status),
+			slog.Duration("duration", time.Since(startedAt)),
+			slog.Int("errors", entry.errors),
+			slog.Int("upstream_calls", entry.upstreamCalls),
+			slog.Int("upstream_cached_calls", entry.cachedCalls),
+		)
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// accessLogExtension records the operation to the access log entry.
+//
+// It must be used after ratelimit.Extension to read the upstream call stats.
+type accessLogExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = accessLogExtension{}
+
+func (accessLogExtension) ExtensionName() string {
+	return "AccessLog"
+}
+
+func (accessLogExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (accessLogExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	entry, ok := ctx.Value(accessLogCtxKey{}).(*accessLogEntry)
+	if !ok {
+		return resp
+	}
+	entry.mux.Lock()
+	defer entry.mux.Unlock()
+	if graphql.HasOperationContext(ctx) {
+		entry.operation = telemetry.OperationName(graphql.GetOperationContext(ctx))
+	}
+	if resp != nil {
+		entry.errors += len(resp.Errors)
+	}
+	if stats, ok := ratelimit.StatsFromContext(ctx); ok {
+		entry.upstreamCalls, entry.cachedCalls = stats.Calls()
+	}
+	return resp
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestAccessLog(t *testing.T) {
+	githubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"plan":{"name":"enterprise"}}`)
+	}))
+	defer githubSrv.Close()
+	logs := new(bytes.Buffer)
+	logger, err := NewLogger(logs, LogFormatJSON, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := Handler(WithGitHubEnterprise(githubSrv.URL, githubSrv.URL), WithLogger(logger))
+	req := httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(`{"query":"query myQuery { a: test__organization(login: \"test-org\") { plan { name } } b: test__organization(login: \"test-org\") { plan { name } } }"}`))
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("authorization", "Bearer secret-token")
+	req.Header.Set("apollographql-client-name", "router")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code: got=%d", rec.Code)
+	}
+
+	if strings.Contains(logs.String(), "secret-token") {
+		t.Errorf("the token is logged:\n%s", logs.String())
+	}
+	var got map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &got); err != nil {
+		t.Fatalf("cannot decode log: %v\n%s", err, logs.String())
+	}
+	want := map[string]any{
+		"level":                 "INFO",
+		"msg":                   "graphql request",
+		"operation":             "myQuery",
+		"client_name":           "router",
+		"client_version":        "",
+		"token_fingerprint":     authz.Fingerprint("Bearer secret-token"),
+		"method":                http.MethodPost,
+		"status":                float64(http.StatusOK),
+		"errors":                float64(0),
+		"upstream_calls":        float64(1),
+		"upstream_cached_calls": float64(0),
+	}
+	if diff := cmp.Diff(got, want, cmpopts.IgnoreMapEntries(func(k string, _ any) bool { return k == "time" || k == "duration" })); diff != "" {
+		t.Errorf("log (-got, +want):\n%s", diff)
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	type testCase struct {
+		format  string
+		level   string
+		wantErr error
+	}
+	testCases := []testCase{
+		{LogFormatJSON, "debug", nil},
+		{LogFormatText, "WARN", nil},
+		{"logfmt", "info", ErrUnknownLogFormat},
+		{LogFormatJSON, "verbose", ErrUnknownLogLevel},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format+"/"+tc.level, func(t *testing.T) {
+			_, err := NewLogger(new(bytes.Buffer), tc.format, tc.level)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("error: got=%v want=%v", err, tc.wantErr)
+			}
+		})
+	}
+}
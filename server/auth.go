@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errMsgAuthenticationRequired = "authentication required: send a GitHub token in the Authorization header"
+
+// authenticates reports whether the request is authenticated enough for strict mode.
+//
+// The caller must send its own credential; the GitHub App standing in for anonymous callers counts only if the operator allows it explicitly.
+// The trusted callers in production mode are not authenticated, but they are let through serviceOnly to fetch _service.
+func (o *options) authenticates(authzHeader string) bool {
+	if _, found := authz.ParseAuthorization(authzHeader); found {
+		return true
+	}
+	return o.strictAuthAllowsApp && o.app != nil && o.appAuthMode.Uses(authzHeader)
+}
+
+// writeUnauthenticated rejects the GraphQL request with 401 before executing it.
+func writeUnauthenticated(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	w.Header().Set("www-authenticate", authz.SchemeBearer)
+	w.WriteHeader(http.StatusUnauthorized)
+	_ = json.NewEncoder(w).Encode(&graphql.Response{Errors: gqlerror.List{{
+		Message:    errMsgAuthenticationRequired,
+		Extensions: map[string]interface{}{"code": resolvers.CodeUnauthenticated},
+	}}})
+}
+
+type serviceOnlyCtxKey struct{}
+
+// serviceOnlyGrant tells serviceOnly whether serviceOnlyGuard rejected the operation.
+type serviceOnlyGrant struct {
+	rejected bool
+}
+
+// serviceOnly lets serviceOnlyGuard reject the operations other than _service of the request without a credential,
+// and responds 401 if it does.
+func serviceOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		grant := &serviceOnlyGrant{}
+		h.ServeHTTP(&serviceOnlyWriter{ResponseWriter: w, grant: grant}, r.WithContext(context.WithValue(r.Context(), serviceOnlyCtxKey{}, grant)))
+	})
+}
+
+type serviceOnlyWriter struct {
+	http.ResponseWriter
+	grant       *serviceOnlyGrant
+	wroteHeader bool
+}
+
+func (w *serviceOnlyWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if w.grant.rejected {
+		w.Header().Set("www-authenticate", authz.SchemeBearer)
+		status = http.StatusUnauthorized
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *serviceOnlyWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.ResponseWriter.Write(b)
+}
+
+// serviceOnlyGuard rejects the operations selecting anything other than _service and __typename
+// of the requests let through serviceOnly.
+type serviceOnlyGuard struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = serviceOnlyGuard{}
+
+func (serviceOnlyGuard) ExtensionName() string {
+	return "ServiceOnlyGuard"
+}
+
+func (serviceOnlyGuard) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (serviceOnlyGuard) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	grant, ok := ctx.Value(serviceOnlyCtxKey{}).(*serviceOnlyGrant)
+	if !ok {
+		return nil
+	}
+	if op := oc.Doc.Operations.ForName(oc.OperationName); op != nil && op.Operation == ast.Query && selectsOnly(op.SelectionSet, "_service", "__typename") {
+		return nil
+	}
+	grant.rejected = true
+	err := gqlerror.Errorf(errMsgAuthenticationRequired)
+	errcode.Set(err, resolvers.CodeUnauthenticated)
+	return err
+}
+
+func selectsOnly(selectionSet ast.SelectionSet, names ...string) bool {
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if !slices.Contains(names, s.Name) {
+				return false
+			}
+		case *ast.FragmentSpread:
+			if s.Definition == nil || !selectsOnly(s.Definition.SelectionSet, names...) {
+				return false
+			}
+		case *ast.InlineFragment:
+			if !selectsOnly(s.SelectionSet, names...) {
+				return false
+			}
+		}
+	}
+	return true
+}
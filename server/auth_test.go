@@ -0,0 +1,237 @@
+package server
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/aereal/github-graphql-proxy/githubtest"
+	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandler_strictAuth(t *testing.T) {
+	type testCase struct {
+		name        string
+		opts        []Option
+		authzHeader string
+		secret      string
+		query       string
+		wantStatus  int
+	}
+	strict := WithStrictAuth(true)
+	production := WithProductionMode([]netip.Prefix{}, "s3cret")
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("octocat:0xdeadbeaf"))
+	withApp := WithGitHubApp(newTestApp(t, "http://github.test/api/v3/"), authz.AppAuthFallback)
+	testCases := []testCase{
+		{"not strict", nil, "", "", `{ __typename }`, http.StatusOK},
+		{"anonymous", []Option{strict}, "", "", `{ __typename }`, http.StatusUnauthorized},
+		{"unknown scheme", []Option{strict}, "Digest username=octocat", "", `{ __typename }`, http.StatusUnauthorized},
+		{"empty token", []Option{strict}, "Bearer ", "", `{ __typename }`, http.StatusUnauthorized},
+		{"Bearer", []Option{strict}, "Bearer 0xdeadbeaf", "", `{ __typename }`, http.StatusOK},
+		{"lowercase bearer", []Option{strict}, "bearer 0xdeadbeaf", "", `{ __typename }`, http.StatusOK},
+		{"token", []Option{strict}, "token 0xdeadbeaf", "", `{ __typename }`, http.StatusOK},
+		{"Basic", []Option{strict}, basic, "", `{ __typename }`, http.StatusOK},
+		{"trusted caller in production", []Option{strict, production}, "", "s3cret", `{ _service { sdl } __typename }`, http.StatusOK},
+		{"trusted caller via fragment", []Option{strict, production}, "", "s3cret", `query { ...service } fragment service on Query { _service { sdl } }`, http.StatusOK},
+		{"trusted caller querying others", []Option{strict, production}, "", "s3cret", `{ _service { sdl } test__organization(login: "test-org") { login } }`, http.StatusUnauthorized},
+		{"untrusted caller in production", []Option{strict, production}, "", "", `{ _service { sdl } }`, http.StatusUnauthorized},
+		{"anonymous with GitHub App", []Option{strict, withApp}, "", "", `{ __typename }`, http.StatusUnauthorized},
+		{"anonymous with GitHub App allowed", []Option{strict, withApp, WithStrictAuthAllowingApp(true)}, "", "", `{ __typename }`, http.StatusOK},
+		{"Bearer with GitHub App", []Option{strict, withApp}, "Bearer 0xdeadbeaf", "", `{ __typename }`, http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := Handler(tc.opts...)
+			reqBody, err := json.Marshal(map[string]string{"query": tc.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(string(reqBody)))
+			req.Header.Set("content-type", "application/json")
+			if tc.authzHeader != "" {
+				req.Header.Set("authorization", tc.authzHeader)
+			}
+			if tc.secret != "" {
+				req.Header.Set(ServiceSecretHeader, tc.secret)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status code: got=%d want=%d", rec.Code, tc.wantStatus)
+			}
+			if rec.Code != http.StatusUnauthorized {
+				return
+			}
+			var body struct {
+				Errors []struct {
+					Extensions map[string]any
+				}
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			var codes []any
+			for _, e := range body.Errors {
+				codes = append(codes, e.Extensions["code"])
+			}
+			if diff := cmp.Diff(codes, []any{resolvers.CodeUnauthenticated}); diff != "" {
+				t.Errorf("error codes (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandler_forwardsAuthorization(t *testing.T) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("octocat:0xdeadbeaf"))
+	type testCase struct {
+		name        string
+		authzHeader string
+		wantHeader  string
+	}
+	testCases := []testCase{
+		{"Bearer", "Bearer 0xdeadbeaf", "Bearer 0xdeadbeaf"},
+		{"lowercase bearer", "bearer 0xdeadbeaf", "Bearer 0xdeadbeaf"},
+		{"token", "token 0xdeadbeaf", "token 0xdeadbeaf"},
+		{"Basic", basic, basic},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			route := githubtest.GET("/orgs/{org}", githubtest.JSON(http.StatusOK, map[string]any{"login": "test-org", "plan": map[string]any{"name": "team"}})).
+				WithHeader("authorization", tc.wantHeader)
+			githubSrv := githubtest.NewServer(t, route)
+			h := Handler(WithGitHubEnterprise(githubSrv.APIBaseURL(), githubSrv.APIBaseURL()), WithStrictAuth(true), WithCache(0, 0), WithRetry(1, 0))
+			req := httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(`{"query":"{ test__organization(login: \"test-org\") { plan { name } } }"}`))
+			req.Header.Set("content-type", "application/json")
+			req.Header.Set("authorization", tc.authzHeader)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code: got=%d", rec.Code)
+			}
+			if got := route.Calls(); got != 1 {
+				t.Errorf("calls: got=%d", got)
+			}
+		})
+	}
+}
+
+func TestHandler_gitHubApp(t *testing.T) {
+	installation := githubtest.GET("/orgs/{org}/installation", githubtest.JSON(http.StatusOK, map[string]any{"id": 42}))
+	accessToken := &githubtest.Route{
+		Method:    http.MethodPost,
+		Path:      "/app/installations/{id}/access_tokens",
+		Responses: []*githubtest.Response{githubtest.JSON(http.StatusCreated, map[string]any{"token": "ghs_42", "expires_at": time.Now().Add(time.Hour).Format(time.RFC3339)})},
+	}
+	org := githubtest.GET("/orgs/{org}", githubtest.JSON(http.StatusOK, map[string]any{"login": "test-org", "plan": map[string]any{"name": "team"}})).
+		WithHeader("authorization", "Bearer ghs_42")
+	readiness := githubtest.GET("/rate_limit", githubtest.JSON(http.StatusOK, map[string]any{})).
+		WithHeader("authorization", "Bearer readiness-token")
+	githubSrv := githubtest.NewServer(t, installation, accessToken, org, readiness)
+	policy := &authz.Policy{Billing: authz.BillingPolicy{Teams: []string{"test-org/finance"}}}
+	h := Handler(
+		WithGitHubEnterprise(githubSrv.APIBaseURL(), githubSrv.APIBaseURL()),
+		WithGitHubApp(newTestApp(t, githubSrv.APIBaseURL()), authz.AppAuthFallback),
+		WithPolicy(policy),
+		WithReadinessCheck("readiness-token"),
+		WithCache(0, 0),
+		WithRetry(1, 0),
+	)
+
+	type testCase struct {
+		name      string
+		query     string
+		wantData  map[string]any
+		wantCodes map[string]any
+	}
+	testCases := []testCase{
+		{
+			"organization",
+			`{ test__organization(login: "test-org") { plan { name } } }`,
+			map[string]any{"test__organization": map[string]any{"plan": map[string]any{"name": "team"}}},
+			map[string]any{},
+		},
+		{
+			"viewerCredential",
+			`{ viewerCredential { tokenType } }`,
+			nil,
+			map[string]any{"viewerCredential": resolvers.CodeUnsupportedCredential},
+		},
+		{
+			"billing of teams",
+			`{ test__organization(login: "test-org") { billing { storage { estimatedStorageForMonth } } } }`,
+			map[string]any{"test__organization": nil},
+			map[string]any{"test__organization": resolvers.CodePolicyDenied},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(map[string]string{"query": tc.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(string(reqBody)))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status code: got=%d", rec.Code)
+			}
+			var body struct {
+				Data   map[string]any
+				Errors []struct {
+					Path       []any
+					Extensions map[string]any
+				}
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(body.Data, tc.wantData); diff != "" {
+				t.Errorf("data (-got, +want):\n%s", diff)
+			}
+			codes := map[string]any{}
+			for _, e := range body.Errors {
+				codes[e.Path[0].(string)] = e.Extensions["code"]
+			}
+			if diff := cmp.Diff(codes, tc.wantCodes); diff != "" {
+				t.Errorf("error codes (-got, +want):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("readiness", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("status code: got=%d body=%s", rec.Code, rec.Body.String())
+		}
+		if got := readiness.Calls(); got != 1 {
+			t.Errorf("readiness calls: got=%d want=1", got)
+		}
+	})
+}
+
+func newTestApp(t *testing.T, apiBaseURL string) *authz.App {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	app, err := authz.NewApp(1234, keyPEM, authz.WithAppAPIBaseURL(apiBaseURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return app
+}
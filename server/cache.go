@@ -0,0 +1,199 @@
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/ratelimit"
+)
+
+const (
+	cacheStatusRevalidated = "REVALIDATED"
+
+	DefaultCacheSize = 1000
+)
+
+// ResponseCache is a process-wide cache of upstream responses keyed by the token fingerprint and the URL.
+//
+// Entries younger than TTL are served without calling GitHub.
+// Older entries are revalidated with If-None-Match/If-Modified-Since, and 304 Not Modified responses do not count against the rate limit.
+type ResponseCache struct {
+	size int
+	ttl  time.Duration
+
+	mux     sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	hits          int64
+	revalidations int64
+	misses        int64
+}
+
+// CacheStats is a snapshot of ResponseCache counters.
+type CacheStats struct {
+	Hits          int64
+	Revalidations int64
+	Misses        int64
+	Entries       int
+}
+
+// NewResponseCache returns a ResponseCache that holds at most size entries.
+func NewResponseCache(size int, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		size:    size,
+		ttl:     ttl,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+// maxAge returns how long the responses built from the cache can be served without asking GitHub.
+// It is zero if the cache is disabled or always revalidates.
+func (c *ResponseCache) maxAge() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return c.ttl
+}
+
+func (c *ResponseCache) Stats() CacheStats {
+	c.mux.Lock()
+	entries := c.lru.Len()
+	c.mux.Unlock()
+	return CacheStats{
+		Hits:          atomic.LoadInt64(&c.hits),
+		Revalidations: atomic.LoadInt64(&c.revalidations),
+		Misses:        atomic.LoadInt64(&c.misses),
+		Entries:       entries,
+	}
+}
+
+func (c *ResponseCache) transport(fingerprint string, base http.RoundTripper) http.RoundTripper {
+	return &cacheTransport{cache: c, fingerprint: fingerprint, base: base}
+}
+
+func (c *ResponseCache) get(key string) (*cacheEntry, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*cacheEntry), true
+}
+
+func (c *ResponseCache) put(entry *cacheEntry) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if el, ok := c.entries[entry.key]; ok {
+		el.Value = entry
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+type cacheEntry struct {
+	key        string
+	storedAt   time.Time
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func (e *cacheEntry) response(r *http.Request, status string) *http.Response {
+	header := e.header.Clone()
+	header.Set(ratelimit.CachedResponseHeader, status)
+	return &http.Response{
+		Status:        http.StatusText(e.statusCode),
+		StatusCode:    e.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       r,
+	}
+}
+
+type cacheTransport struct {
+	cache       *ResponseCache
+	fingerprint string
+	base        http.RoundTripper
+}
+
+var _ http.RoundTripper = (*cacheTransport)(nil)
+
+func (t *cacheTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet {
+		return t.base.RoundTrip(r)
+	}
+	key := t.fingerprint + " " + r.URL.String() + " " + r.Header.Get("accept")
+	entry, found := t.cache.get(key)
+	if found && time.Since(entry.storedAt) < t.cache.ttl {
+		atomic.AddInt64(&t.cache.hits, 1)
+		return entry.response(r, ratelimit.CacheStatusHit), nil
+	}
+	req := r
+	if found {
+		req = r.Clone(r.Context())
+		if etag := entry.header.Get("etag"); etag != "" {
+			req.Header.Set("if-none-match", etag)
+		}
+		if lastModified := entry.header.Get("last-modified"); lastModified != "" {
+			req.Header.Set("if-modified-since", lastModified)
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if found && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		atomic.AddInt64(&t.cache.revalidations, 1)
+		refreshed := &cacheEntry{key: key, storedAt: time.Now(), statusCode: entry.statusCode, header: entry.header.Clone(), body: entry.body}
+		for _, name := range []string{"x-ratelimit-limit", "x-ratelimit-remaining", "x-ratelimit-reset", "x-ratelimit-used", "x-ratelimit-resource", "date"} {
+			if v := resp.Header.Get(name); v != "" {
+				refreshed.header.Set(name, v)
+			}
+		}
+		t.cache.put(refreshed)
+		return refreshed.response(r, cacheStatusRevalidated), nil
+	}
+	atomic.AddInt64(&t.cache.misses, 1)
+	if !cacheable(resp) {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	t.cache.put(&cacheEntry{key: key, storedAt: time.Now(), statusCode: resp.StatusCode, header: resp.Header.Clone(), body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func cacheable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	if strings.Contains(resp.Header.Get("cache-control"), "no-store") {
+		return false
+	}
+	return resp.Header.Get("etag") != "" || resp.Header.Get("last-modified") != ""
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResponseCache(t *testing.T) {
+	type request struct {
+		fingerprint string
+		path        string
+	}
+	type testCase struct {
+		name      string
+		size      int
+		ttl       time.Duration
+		requests  []request
+		wantCalls int64
+		wantStats CacheStats
+	}
+	testCases := []testCase{
+		{
+			"revalidate",
+			10, 0,
+			[]request{{"a", "/orgs/a"}, {"a", "/orgs/a"}},
+			2,
+			CacheStats{Misses: 1, Revalidations: 1, Entries: 1},
+		},
+		{
+			"fresh",
+			10, time.Hour,
+			[]request{{"a", "/orgs/a"}, {"a", "/orgs/a"}},
+			1,
+			CacheStats{Misses: 1, Hits: 1, Entries: 1},
+		},
+		{
+			"not shared across tokens",
+			10, time.Hour,
+			[]request{{"a", "/orgs/a"}, {"b", "/orgs/a"}},
+			2,
+			CacheStats{Misses: 2, Entries: 2},
+		},
+		{
+			"evict least recently used",
+			1, time.Hour,
+			[]request{{"a", "/orgs/a"}, {"a", "/orgs/b"}, {"a", "/orgs/a"}},
+			3,
+			CacheStats{Misses: 3, Entries: 1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt64(&calls, 1)
+				etag := `"` + r.URL.Path + `"`
+				if r.Header.Get("if-none-match") == etag {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("etag", etag)
+				_, _ = io.WriteString(w, r.URL.Path)
+			}))
+			defer srv.Close()
+			cache := NewResponseCache(tc.size, tc.ttl)
+			for _, req := range tc.requests {
+				client := &http.Client{Transport: cache.transport(req.fingerprint, srv.Client().Transport)}
+				r, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+req.path, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp, err := client.Do(r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if resp.StatusCode != http.StatusOK {
+					t.Errorf("status code: got=%d", resp.StatusCode)
+				}
+				if string(body) != req.path {
+					t.Errorf("body: got=%q want=%q", string(body), req.path)
+				}
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("upstream calls: got=%d want=%d", calls, tc.wantCalls)
+			}
+			if diff := cmp.Diff(cache.Stats(), tc.wantStats); diff != "" {
+				t.Errorf("stats (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type cacheControlCtxKey struct{}
+
+// cacheControlOutcome tells cacheControl whether the operation succeeded.
+//
+// It is false unless the operation is executed without errors, because the errors before the execution such as PersistedQueryNotFound bypass the extensions.
+type cacheControlOutcome struct {
+	mux       sync.Mutex
+	succeeded bool
+}
+
+// cacheControl sets Cache-Control and Vary to the responses of GET requests, such as the automatic persisted queries,
+// because browsers and CDNs may cache them unlike POST requests.
+//
+// The successful responses can be cached as long as the response cache serves them, but only by the caller's own cache
+// because they depend on the caller's credential. The failed ones are never stored.
+func cacheControl(h http.Handler, maxAge time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			h.ServeHTTP(w, r)
+			return
+		}
+		outcome := &cacheControlOutcome{}
+		h.ServeHTTP(&cacheControlWriter{ResponseWriter: w, outcome: outcome, maxAge: maxAge}, r.WithContext(context.WithValue(r.Context(), cacheControlCtxKey{}, outcome)))
+	})
+}
+
+type cacheControlWriter struct {
+	http.ResponseWriter
+	outcome     *cacheControlOutcome
+	maxAge      time.Duration
+	wroteHeader bool
+}
+
+func (w *cacheControlWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.outcome.mux.Lock()
+	succeeded := w.outcome.succeeded
+	w.outcome.mux.Unlock()
+	w.Header().Add("vary", "Authorization")
+	if maxAge := int(w.maxAge.Seconds()); status == http.StatusOK && succeeded && maxAge > 0 {
+		w.Header().Set("cache-control", fmt.Sprintf("private, max-age=%d", maxAge))
+	} else {
+		w.Header().Set("cache-control", "no-store")
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheControlWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.ResponseWriter.Write(b)
+}
+
+// cacheControlExtension tells cacheControl that the operation has no errors.
+type cacheControlExtension struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = cacheControlExtension{}
+
+func (cacheControlExtension) ExtensionName() string {
+	return "CacheControl"
+}
+
+func (cacheControlExtension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (cacheControlExtension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	outcome, ok := ctx.Value(cacheControlCtxKey{}).(*cacheControlOutcome)
+	if !ok {
+		return resp
+	}
+	if resp != nil && len(resp.Errors) == 0 {
+		outcome.mux.Lock()
+		outcome.succeeded = true
+		outcome.mux.Unlock()
+	}
+	return resp
+}
@@ -0,0 +1,386 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/aereal/github-graphql-proxy/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+const redacted = "<redacted>"
+
+// Config is the configuration of the server read from a YAML file and environment variables.
+//
+// Each field can be overridden by the environment variable named in its env tag.
+type Config struct {
+	Addr          string            `yaml:"addr" env:"GITHUB_GRAPHQL_PROXY_ADDR"`
+	Mode          string            `yaml:"mode" env:"GITHUB_GRAPHQL_PROXY_MODE"`
+	StartTimeout  time.Duration     `yaml:"startTimeout" env:"GITHUB_GRAPHQL_PROXY_START_TIMEOUT"`
+	DrainDelay    time.Duration     `yaml:"drainDelay" env:"GITHUB_GRAPHQL_PROXY_DRAIN_DELAY"`
+	Playground    bool              `yaml:"playground" env:"GITHUB_GRAPHQL_PROXY_PLAYGROUND"`
+	Introspection bool              `yaml:"introspection" env:"GITHUB_GRAPHQL_PROXY_INTROSPECTION"`
+	Service       ServiceConfig     `yaml:"service"`
+	Auth          AuthConfig        `yaml:"auth"`
+	Policy        PolicyConfig      `yaml:"policy"`
+	GitHub        GitHubConfig      `yaml:"github"`
+	Cache         CacheConfig       `yaml:"cache"`
+	APQ           APQConfig         `yaml:"apq"`
+	Limits        LimitsConfig      `yaml:"limits"`
+	Retry         RetryConfig       `yaml:"retry"`
+	Concurrency   ConcurrencyConfig `yaml:"concurrency"`
+	Tracing       TracingConfig     `yaml:"tracing"`
+	Log           LogConfig         `yaml:"log"`
+}
+
+// ServiceConfig configures who can query _service in production mode.
+type ServiceConfig struct {
+	TrustedCallers []string `yaml:"trustedCallers" env:"GITHUB_GRAPHQL_PROXY_SERVICE_TRUSTED_CALLERS"`
+	Secret         string   `yaml:"secret" env:"GITHUB_GRAPHQL_PROXY_SERVICE_SECRET"`
+}
+
+type AuthConfig struct {
+	// Strict rejects GraphQL requests without any credential with 401.
+	Strict bool `yaml:"strict" env:"GITHUB_GRAPHQL_PROXY_STRICT_AUTH"`
+	// AllowApp lets strict mode accept the requests without a credential that the GitHub App authenticates.
+	AllowApp bool `yaml:"allowApp" env:"GITHUB_GRAPHQL_PROXY_STRICT_AUTH_ALLOW_APP"`
+}
+
+// PolicyConfig configures the access policy of the organizations and the repositories.
+type PolicyConfig struct {
+	// Path is the path to the YAML file of authz.Policy. Empty allows everything.
+	Path string `yaml:"path" env:"GITHUB_GRAPHQL_PROXY_POLICY_PATH"`
+}
+
+type GitHubConfig struct {
+	APIURL              string          `yaml:"apiURL" env:"GITHUB_API_URL"`
+	UploadURL           string          `yaml:"uploadURL" env:"GITHUB_UPLOAD_URL"`
+	ReadinessCheckToken string          `yaml:"readinessCheckToken" env:"READINESS_CHECK_GITHUB_TOKEN"`
+	App                 GitHubAppConfig `yaml:"app"`
+}
+
+type GitHubAppConfig struct {
+	ID             int64  `yaml:"id" env:"GITHUB_APP_ID"`
+	PrivateKeyPath string `yaml:"privateKeyPath" env:"GITHUB_APP_PRIVATE_KEY_PATH"`
+	AuthMode       string `yaml:"authMode" env:"GITHUB_APP_AUTH_MODE"`
+}
+
+type CacheConfig struct {
+	Size int           `yaml:"size" env:"GITHUB_GRAPHQL_PROXY_CACHE_SIZE"`
+	TTL  time.Duration `yaml:"ttl" env:"GITHUB_GRAPHQL_PROXY_CACHE_TTL"`
+}
+
+type APQConfig struct {
+	CacheSize int `yaml:"cacheSize" env:"GITHUB_GRAPHQL_PROXY_APQ_CACHE_SIZE"`
+}
+
+type LimitsConfig struct {
+	MaxComplexity int `yaml:"maxComplexity" env:"GITHUB_GRAPHQL_PROXY_MAX_COMPLEXITY"`
+	MaxDepth      int `yaml:"maxDepth" env:"GITHUB_GRAPHQL_PROXY_MAX_DEPTH"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"maxAttempts" env:"GITHUB_GRAPHQL_PROXY_RETRY_MAX_ATTEMPTS"`
+	MaxWait     time.Duration `yaml:"maxWait" env:"GITHUB_GRAPHQL_PROXY_RETRY_MAX_WAIT"`
+}
+
+type ConcurrencyConfig struct {
+	Max      int64 `yaml:"max" env:"GITHUB_GRAPHQL_PROXY_MAX_CONCURRENCY"`
+	PerToken int64 `yaml:"perToken" env:"GITHUB_GRAPHQL_PROXY_MAX_CONCURRENCY_PER_TOKEN"`
+	// Resolvers is the max number of resolvers running concurrently in an operation.
+	Resolvers int64 `yaml:"resolvers" env:"GITHUB_GRAPHQL_PROXY_MAX_RESOLVER_CONCURRENCY"`
+}
+
+type TracingConfig struct {
+	Exporter     string `yaml:"exporter" env:"GITHUB_GRAPHQL_PROXY_TRACE_EXPORTER"`
+	OTLPEndpoint string `yaml:"otlpEndpoint" env:"GITHUB_GRAPHQL_PROXY_TRACE_OTLP_ENDPOINT"`
+}
+
+type LogConfig struct {
+	Format string `yaml:"format" env:"GITHUB_GRAPHQL_PROXY_LOG_FORMAT"`
+	Level  string `yaml:"level" env:"GITHUB_GRAPHQL_PROXY_LOG_LEVEL"`
+}
+
+// DefaultConfig returns the configuration used when neither a file nor environment variables are given.
+func DefaultConfig() Config {
+	return Config{
+		Addr:          ":8080",
+		Mode:          ModeDevelopment,
+		StartTimeout:  time.Second * 5,
+		DrainDelay:    DefaultDrainDelay,
+		Playground:    true,
+		Introspection: true,
+		GitHub: GitHubConfig{
+			App: GitHubAppConfig{AuthMode: authz.AppAuthFallback.String()},
+		},
+		Cache:       CacheConfig{Size: DefaultCacheSize},
+		APQ:         APQConfig{CacheSize: DefaultPersistedQueryCacheSize},
+		Limits:      LimitsConfig{MaxComplexity: DefaultMaxComplexity, MaxDepth: DefaultMaxDepth},
+		Retry:       RetryConfig{MaxAttempts: DefaultRetryMaxAttempts, MaxWait: DefaultRetryMaxWait},
+		Concurrency: ConcurrencyConfig{Max: DefaultMaxConcurrency, PerToken: DefaultMaxConcurrencyPerToken(), Resolvers: DefaultResolverConcurrency},
+		Tracing:     TracingConfig{Exporter: tracing.ExporterNone},
+		Log:         LogConfig{Format: LogFormatJSON, Level: "info"},
+	}
+}
+
+// LoadConfigFile overwrites cfg with the values in the YAML file at path. Unknown keys are rejected.
+func LoadConfigFile(path string, cfg *Config) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidConfig, path, err)
+	}
+	return nil
+}
+
+// ApplyEnv overwrites cfg with the environment variables looked up by lookup such as os.LookupEnv.
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	return applyEnv(reflect.ValueOf(c).Elem(), lookup)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func applyEnv(v reflect.Value, lookup func(string) (string, bool)) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, fv := t.Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(fv, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		name := field.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+		s, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(fv, s); err != nil {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidConfig, name, err)
+		}
+	}
+	return nil
+}
+
+func setValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v.SetInt(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// Validate reports all invalid values in the configuration at once.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: %s: %s", ErrInvalidConfig, key, fmt.Sprintf(format, args...)))
+	}
+	if c.Addr == "" {
+		invalid("addr", "must not be empty")
+	}
+	switch c.Mode {
+	case ModeDevelopment, ModeProduction:
+	default:
+		invalid("mode", "must be %s or %s: %q", ModeDevelopment, ModeProduction, c.Mode)
+	}
+	for _, caller := range c.Service.TrustedCallers {
+		if _, err := ParseTrustedCaller(caller); err != nil {
+			invalid("service.trustedCallers", "%s", err)
+		}
+	}
+	if c.StartTimeout <= 0 {
+		invalid("startTimeout", "must be positive: %s", c.StartTimeout)
+	}
+	if c.DrainDelay < 0 {
+		invalid("drainDelay", "must not be negative: %s", c.DrainDelay)
+	}
+	if c.GitHub.APIURL != "" {
+		if _, err := parseUpstreamURL(c.GitHub.APIURL); err != nil {
+			invalid("github.apiURL", "%s", err)
+		}
+	}
+	if c.GitHub.UploadURL != "" {
+		if c.GitHub.APIURL == "" {
+			invalid("github.uploadURL", "requires github.apiURL")
+		} else if _, err := parseUpstreamURL(c.GitHub.UploadURL); err != nil {
+			invalid("github.uploadURL", "%s", err)
+		}
+	}
+	if _, err := authz.ParseAppAuthMode(c.GitHub.App.AuthMode); err != nil {
+		invalid("github.app.authMode", "%s", err)
+	}
+	if c.GitHub.App.ID < 0 {
+		invalid("github.app.id", "must not be negative: %d", c.GitHub.App.ID)
+	}
+	if c.GitHub.App.ID != 0 && c.GitHub.App.PrivateKeyPath == "" {
+		invalid("github.app.privateKeyPath", "is required when github.app.id is set")
+	}
+	if c.Cache.TTL < 0 {
+		invalid("cache.ttl", "must not be negative: %s", c.Cache.TTL)
+	}
+	if c.Retry.MaxWait < 0 {
+		invalid("retry.maxWait", "must not be negative: %s", c.Retry.MaxWait)
+	}
+	if c.Concurrency.Max < 0 {
+		invalid("concurrency.max", "must not be negative: %d", c.Concurrency.Max)
+	}
+	if c.Concurrency.PerToken < 0 {
+		invalid("concurrency.perToken", "must not be negative: %d", c.Concurrency.PerToken)
+	}
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		invalid("tracing.exporter", "must be one of %s, %s or %s: %q", tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP, c.Tracing.Exporter)
+	}
+	if _, err := NewLogger(io.Discard, c.Log.Format, c.Log.Level); err != nil {
+		invalid("log", "%s", err)
+	}
+	return errors.Join(errs...)
+}
+
+// Redacted returns a copy of the configuration whose secrets are masked.
+func (c Config) Redacted() Config {
+	if c.Service.Secret != "" {
+		c.Service.Secret = redacted
+	}
+	if c.GitHub.ReadinessCheckToken != "" {
+		c.GitHub.ReadinessCheckToken = redacted
+	}
+	return c
+}
+
+// Effective returns a copy of the configuration with the values overridden by the mode.
+func (c Config) Effective() Config {
+	if c.Mode == ModeProduction {
+		c.Playground = false
+		c.Introspection = false
+	}
+	return c
+}
+
+// Write writes the effective configuration to w in YAML with its secrets masked.
+func (c Config) Write(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(c.Effective().Redacted()); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Options returns the options that make Handler and Start behave as configured.
+func (c Config) Options() ([]Option, error) {
+	opts := []Option{
+		WithCache(c.Cache.Size, c.Cache.TTL),
+		WithPersistedQueries(c.APQ.CacheSize),
+		WithQueryLimits(c.Limits.MaxComplexity, c.Limits.MaxDepth),
+		WithRetry(c.Retry.MaxAttempts, c.Retry.MaxWait),
+		WithConcurrency(c.Concurrency.PerToken, c.Concurrency.Max),
+		WithResolverConcurrency(c.Concurrency.Resolvers),
+		WithDrainDelay(c.DrainDelay),
+		WithReadinessCheck(c.GitHub.ReadinessCheckToken),
+		WithPlayground(c.Playground),
+		WithIntrospection(c.Introspection),
+		WithStrictAuth(c.Auth.Strict),
+		WithStrictAuthAllowingApp(c.Auth.AllowApp),
+	}
+	if c.Mode == ModeProduction {
+		trustedCallers := make([]netip.Prefix, 0, len(c.Service.TrustedCallers))
+		for _, caller := range c.Service.TrustedCallers {
+			prefix, err := ParseTrustedCaller(caller)
+			if err != nil {
+				return nil, err
+			}
+			trustedCallers = append(trustedCallers, prefix)
+		}
+		opts = append(opts, WithProductionMode(trustedCallers, c.Service.Secret))
+	}
+	if c.Policy.Path != "" {
+		policy, err := authz.LoadPolicyFile(c.Policy.Path)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithPolicy(policy))
+	}
+	var appOpts []authz.AppOption
+	if c.GitHub.APIURL != "" {
+		uploadURL := c.GitHub.UploadURL
+		if uploadURL == "" {
+			var err error
+			uploadURL, err = DefaultUploadURL(c.GitHub.APIURL)
+			if err != nil {
+				return nil, err
+			}
+		}
+		opts = append(opts, WithGitHubEnterprise(c.GitHub.APIURL, uploadURL))
+		appOpts = append(appOpts, authz.WithAppAPIBaseURL(c.GitHub.APIURL))
+	}
+	if c.GitHub.App.ID != 0 {
+		mode, err := authz.ParseAppAuthMode(c.GitHub.App.AuthMode)
+		if err != nil {
+			return nil, err
+		}
+		key, err := os.ReadFile(c.GitHub.App.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("read GitHub App private key: %w", err)
+		}
+		app, err := authz.NewApp(c.GitHub.App.ID, key, appOpts...)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithGitHubApp(app, mode))
+	}
+	return opts, nil
+}
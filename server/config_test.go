@@ -0,0 +1,149 @@
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	type testCase struct {
+		name    string
+		file    string
+		env     map[string]string
+		want    func(cfg *Config)
+		wantErr string
+	}
+	testCases := []testCase{
+		{"defaults", "", nil, func(cfg *Config) {}, ""},
+		{
+			"file",
+			"cache:\n  size: 10\n  ttl: 30s\nplayground: false\ngithub:\n  apiURL: https://ghes.example.com/api/v3/\n",
+			nil,
+			func(cfg *Config) {
+				cfg.Cache = CacheConfig{Size: 10, TTL: time.Second * 30}
+				cfg.Playground = false
+				cfg.GitHub.APIURL = "https://ghes.example.com/api/v3/"
+			},
+			"",
+		},
+		{
+			"env overrides file",
+			"cache:\n  size: 10\n",
+			map[string]string{"GITHUB_GRAPHQL_PROXY_CACHE_SIZE": "20", "GITHUB_GRAPHQL_PROXY_INTROSPECTION": "false", "GITHUB_GRAPHQL_PROXY_RETRY_MAX_WAIT": "1m"},
+			func(cfg *Config) {
+				cfg.Cache.Size = 20
+				cfg.Introspection = false
+				cfg.Retry.MaxWait = time.Minute
+			},
+			"",
+		},
+		{"unknown key", "cach:\n  size: 10\n", nil, nil, "field cach not found"},
+		{"invalid env", "", map[string]string{"GITHUB_GRAPHQL_PROXY_CACHE_TTL": "1 minute"}, nil, `GITHUB_GRAPHQL_PROXY_CACHE_TTL: invalid duration "1 minute"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := DefaultConfig()
+			err := func() error {
+				if tc.file != "" {
+					path := filepath.Join(t.TempDir(), "config.yml")
+					if err := os.WriteFile(path, []byte(tc.file), 0o600); err != nil {
+						t.Fatal(err)
+					}
+					if err := LoadConfigFile(path, &got); err != nil {
+						return err
+					}
+				}
+				return got.ApplyEnv(func(name string) (string, bool) {
+					v, ok := tc.env[name]
+					return v, ok
+				})
+			}()
+			if tc.wantErr != "" {
+				if err == nil || !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error: got=%v want=%q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := DefaultConfig()
+			tc.want(&want)
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("config (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	type testCase struct {
+		name     string
+		modify   func(cfg *Config)
+		wantErrs []string
+	}
+	testCases := []testCase{
+		{"defaults", func(cfg *Config) {}, nil},
+		{
+			"invalid values",
+			func(cfg *Config) {
+				cfg.Concurrency.PerToken = -1
+				cfg.GitHub.APIURL = "ghes.example.com"
+				cfg.Log.Format = "xml"
+				cfg.GitHub.App.ID = 1
+			},
+			[]string{
+				"invalid config: github.apiURL: invalid upstream URL: scheme must be http or https",
+				"invalid config: github.app.privateKeyPath: is required when github.app.id is set",
+				"invalid config: concurrency.perToken: must not be negative: -1",
+				`invalid config: log: unknown log format: "xml"`,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tc.modify(&cfg)
+			err := cfg.Validate()
+			var got []string
+			if err != nil {
+				got = strings.Split(err.Error(), "\n")
+			}
+			for i := range got {
+				if i < len(tc.wantErrs) && strings.HasPrefix(got[i], tc.wantErrs[i]) {
+					got[i] = tc.wantErrs[i]
+				}
+			}
+			if diff := cmp.Diff(got, tc.wantErrs); diff != "" {
+				t.Errorf("errors (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_Write(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.GitHub.ReadinessCheckToken = "readiness-token"
+	cfg.Service.Secret = "service-secret"
+	var sb strings.Builder
+	if err := cfg.Write(&sb); err != nil {
+		t.Fatal(err)
+	}
+	out := sb.String()
+	for _, secret := range []string{"readiness-token", "service-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("%s is not redacted:\n%s", secret, out)
+		}
+	}
+	for _, want := range []string{"readinessCheckToken: <redacted>", "secret: <redacted>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q is missing:\n%s", want, out)
+		}
+	}
+}
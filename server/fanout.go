@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"golang.org/x/sync/semaphore"
+)
+
+// DefaultResolverConcurrency is the default number of resolvers that can run concurrently in an operation.
+const DefaultResolverConcurrency = 16
+
+// resolverFanOutLimit bounds how many resolvers run concurrently in an operation,
+// such as the fields of hundreds of entities the router asks at once.
+//
+// A resolver holds the semaphore only while it runs, so the child fields resolved after it returns never wait for their parent.
+type resolverFanOutLimit struct {
+	max int64
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = resolverFanOutLimit{}
+
+type fanOutSemaphoreCtxKey struct{}
+
+func (resolverFanOutLimit) ExtensionName() string {
+	return "ResolverFanOutLimit"
+}
+
+func (resolverFanOutLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l resolverFanOutLimit) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if l.max <= 0 {
+		return next(ctx)
+	}
+	return next(context.WithValue(ctx, fanOutSemaphoreCtxKey{}, semaphore.NewWeighted(l.max)))
+}
+
+func (resolverFanOutLimit) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	sem, ok := ctx.Value(fanOutSemaphoreCtxKey{}).(*semaphore.Weighted)
+	if fc := graphql.GetFieldContext(ctx); !ok || fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	if err := sem.Acquire(ctx, 1); err != nil {
+		return nil, fmt.Errorf("resolver cancelled: %w", err)
+	}
+	defer sem.Release(1)
+	return next(ctx)
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestResolverFanOutLimit(t *testing.T) {
+	type testCase struct {
+		name       string
+		max        int64
+		isResolver bool
+		want       int64
+	}
+	testCases := []testCase{
+		{"limited", 3, true, 3},
+		{"unlimited", 0, true, 10},
+		{"not a resolver", 3, false, 10},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ext := resolverFanOutLimit{max: tc.max}
+			var running, peak int64
+			resolver := func(ctx context.Context) (interface{}, error) {
+				n := atomic.AddInt64(&running, 1)
+				defer atomic.AddInt64(&running, -1)
+				for {
+					p := atomic.LoadInt64(&peak)
+					if n <= p || atomic.CompareAndSwapInt64(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond * 50)
+				return nil, nil
+			}
+			ext.InterceptResponse(context.Background(), func(ctx context.Context) *graphql.Response {
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						fieldCtx := graphql.WithFieldContext(ctx, &graphql.FieldContext{IsResolver: tc.isResolver})
+						if _, err := ext.InterceptField(fieldCtx, resolver); err != nil {
+							t.Error(err)
+						}
+					}()
+				}
+				wg.Wait()
+				return &graphql.Response{}
+			})
+			if peak != tc.want {
+				t.Errorf("peak concurrency: got=%d want=%d", peak, tc.want)
+			}
+		})
+	}
+}
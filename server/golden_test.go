@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/aereal/github-graphql-proxy/githubtest"
+	"github.com/google/go-cmp/cmp"
+)
+
+var update = flag.Bool("update", false, "rewrite expected.json of the golden test cases")
+
+// goldenUpstreamURL replaces the URL of the fake GitHub in the responses because it listens on a random port.
+const goldenUpstreamURL = "http://github.test"
+
+// TestHandler_golden runs each testdata/<case>/ through the handler against the fake GitHub.
+//
+// A case consists of:
+//   - query.graphql: the operation to send
+//   - variables.json: the variables of the operation (optional)
+//   - upstream.json: the list of githubtest.Route the fake GitHub answers (optional)
+//   - policy.yaml: the authz.Policy of the handler (optional)
+//   - authorization: the authorization header of the request (optional)
+//   - expected.json: the response of the handler, rewritten with -update
+func TestHandler_golden(t *testing.T) {
+	queries, err := filepath.Glob(filepath.Join("testdata", "*", "query.graphql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) == 0 {
+		t.Fatal("no golden test cases found")
+	}
+	for _, queryPath := range queries {
+		dir := filepath.Dir(queryPath)
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			query, err := os.ReadFile(queryPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params := map[string]any{"query": string(query)}
+			var variables map[string]any
+			if ok := readGoldenJSON(t, filepath.Join(dir, "variables.json"), &variables); ok {
+				params["variables"] = variables
+			}
+			var routes []*githubtest.Route
+			readGoldenJSON(t, filepath.Join(dir, "upstream.json"), &routes)
+			githubSrv := githubtest.NewServer(t, routes...)
+
+			opts := []Option{
+				WithGitHubEnterprise(githubSrv.APIBaseURL(), githubSrv.APIBaseURL()),
+				WithCache(0, 0),
+				WithRetry(1, 0),
+				WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+			}
+			if policyPath := filepath.Join(dir, "policy.yaml"); fileExists(t, policyPath) {
+				policy, err := authz.LoadPolicyFile(policyPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				opts = append(opts, WithPolicy(policy))
+			}
+			h := Handler(opts...)
+			body, err := json.Marshal(params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/extension/query", bytes.NewReader(body))
+			req.Header.Set("content-type", "application/json")
+			authzHeader := "Bearer golden-test-token"
+			if authzPath := filepath.Join(dir, "authorization"); fileExists(t, authzPath) {
+				b, err := os.ReadFile(authzPath)
+				if err != nil {
+					t.Fatal(err)
+				}
+				authzHeader = strings.TrimSpace(string(b))
+			}
+			req.Header.Set("authorization", authzHeader)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("status code: got=%d", rec.Code)
+			}
+			respBody := bytes.ReplaceAll(rec.Body.Bytes(), []byte(githubSrv.URL), []byte(goldenUpstreamURL))
+			var got any
+			if err := json.Unmarshal(respBody, &got); err != nil {
+				t.Fatalf("cannot decode the response: %s\n%s", err, respBody)
+			}
+			sortGoldenErrors(got)
+
+			expectedPath := filepath.Join(dir, "expected.json")
+			if *update {
+				b, err := json.MarshalIndent(got, "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(expectedPath, append(b, '\n'), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var want any
+			if !readGoldenJSON(t, expectedPath, &want) {
+				t.Fatalf("%s is missing; run the test with -update to create it", expectedPath)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("response (-got, +want):\n%s", diff)
+			}
+			for _, route := range routes {
+				if route.Calls() == 0 {
+					t.Errorf("%s is not called", route.Path)
+				}
+			}
+		})
+	}
+}
+
+// sortGoldenErrors sorts the errors by their paths because the root fields are resolved concurrently.
+func sortGoldenErrors(resp any) {
+	m, ok := resp.(map[string]any)
+	if !ok {
+		return
+	}
+	errs, ok := m["errors"].([]any)
+	if !ok {
+		return
+	}
+	pathOf := func(i int) string {
+		if e, ok := errs[i].(map[string]any); ok {
+			return fmt.Sprint(e["path"])
+		}
+		return ""
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return pathOf(i) < pathOf(j) })
+}
+
+func fileExists(t *testing.T, path string) bool {
+	t.Helper()
+	_, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return true
+}
+
+func readGoldenJSON(t *testing.T, path string, v any) bool {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("decode %s: %s", path, err)
+	}
+	return true
+}
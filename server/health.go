@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/aereal/github-graphql-proxy/authz"
+)
+
+const (
+	DefaultDrainDelay = time.Second * 5
+
+	readinessCheckTimeout = time.Second * 5
+)
+
+// health tells whether the server is alive and ready to take traffic.
+type health struct {
+	draining int32
+	// checkUpstream is optional; it checks the upstream is reachable.
+	checkUpstream func(ctx context.Context) error
+}
+
+func newHealth(o *options) *health {
+	h := &health{}
+	if o.readinessToken != "" {
+		h.checkUpstream = func(ctx context.Context) error {
+			client, err := o.newGitHubClient(authz.ProxiedHTTPClient(ctx, "Bearer "+o.readinessToken))
+			if err != nil {
+				return err
+			}
+			if _, _, err := client.RateLimits(ctx); err != nil {
+				return fmt.Errorf("%w: %s", ErrUpstreamNotReached, err)
+			}
+			return nil
+		}
+	}
+	return h
+}
+
+// drain makes the readiness probe fail so the load balancer stops sending new requests.
+func (h *health) drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+func (h *health) isDraining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+func (h *health) liveness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "text/plain")
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+func (h *health) readiness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "text/plain")
+		if h.isDraining() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, "draining")
+			return
+		}
+		if h.checkUpstream != nil {
+			ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+			defer cancel()
+			if err := h.checkUpstream(ctx); err != nil {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintln(w, err.Error())
+				return
+			}
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
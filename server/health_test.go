@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealth(t *testing.T) {
+	type testCase struct {
+		name       string
+		path       string
+		draining   bool
+		checkErr   error
+		wantStatus int
+	}
+	testCases := []testCase{
+		{"liveness", "/healthz", false, nil, http.StatusOK},
+		{"liveness while draining", "/healthz", true, nil, http.StatusOK},
+		{"readiness", "/readyz", false, nil, http.StatusOK},
+		{"readiness while draining", "/readyz", true, nil, http.StatusServiceUnavailable},
+		{"readiness with unreachable upstream", "/readyz", false, errors.New("oops"), http.StatusServiceUnavailable},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := newOptions(nil)
+			o.health.checkUpstream = func(ctx context.Context) error { return tc.checkErr }
+			if tc.draining {
+				o.health.drain()
+			}
+			rec := httptest.NewRecorder()
+			newHandler(o).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status code: got=%d want=%d", rec.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHealth_readinessCheck(t *testing.T) {
+	var gotAuthz string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuthz = r.Header.Get("authorization")
+		if r.URL.Path != "/api/v3/rate_limit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("content-type", "application/json")
+		_, _ = w.Write([]byte(`{"resources":{}}`))
+	}))
+	defer srv.Close()
+	o := newOptions([]Option{WithGitHubEnterprise(srv.URL+"/api/v3/", ""), WithReadinessCheck("service-token")})
+	rec := httptest.NewRecorder()
+	newHandler(o).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status code: got=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if want := "Bearer service-token"; gotAuthz != want {
+		t.Errorf("authorization: got=%q want=%q", gotAuthz, want)
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+)
+
+const DefaultMaxConcurrency = 64
+
+// DefaultMaxConcurrencyPerToken returns the default number of concurrent upstream calls allowed for each token.
+func DefaultMaxConcurrencyPerToken() int64 {
+	return int64(runtime.GOMAXPROCS(0))
+}
+
+// ConcurrencyLimiter bounds concurrent upstream calls across the process.
+//
+// Each token fingerprint has its own limit, and all tokens share the global ceiling.
+type ConcurrencyLimiter struct {
+	perToken int64
+	global   *semaphore.Weighted
+
+	mux    sync.Mutex
+	tokens map[string]*tokenSemaphore
+
+	waiting   int64
+	acquired  int64
+	waitNanos int64
+}
+
+type tokenSemaphore struct {
+	sem  *semaphore.Weighted
+	refs int
+}
+
+// LimiterStats is a snapshot of ConcurrencyLimiter counters.
+type LimiterStats struct {
+	// Waiting is the number of upstream calls waiting for a slot.
+	Waiting int64
+	// Acquired is the number of upstream calls that have got a slot.
+	Acquired int64
+	// TotalWait is the accumulated time upstream calls waited for a slot.
+	TotalWait time.Duration
+}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter. A limit of zero or less means no limit of the kind.
+func NewConcurrencyLimiter(perToken, global int64) *ConcurrencyLimiter {
+	l := &ConcurrencyLimiter{perToken: perToken, tokens: map[string]*tokenSemaphore{}}
+	if global > 0 {
+		l.global = semaphore.NewWeighted(global)
+	}
+	return l
+}
+
+func (l *ConcurrencyLimiter) Stats() LimiterStats {
+	return LimiterStats{
+		Waiting:   atomic.LoadInt64(&l.waiting),
+		Acquired:  atomic.LoadInt64(&l.acquired),
+		TotalWait: time.Duration(atomic.LoadInt64(&l.waitNanos)),
+	}
+}
+
+// acquire waits for a slot of the token and the global one. The returned func must be called to release them.
+func (l *ConcurrencyLimiter) acquire(ctx context.Context, fingerprint string) (func(), error) {
+	atomic.AddInt64(&l.waiting, 1)
+	startedAt := time.Now()
+	defer func() {
+		atomic.AddInt64(&l.waiting, -1)
+		atomic.AddInt64(&l.waitNanos, int64(time.Since(startedAt)))
+	}()
+
+	releaseToken := func() {}
+	if l.perToken > 0 {
+		ts := l.tokenSemaphore(fingerprint)
+		if err := ts.sem.Acquire(ctx, 1); err != nil {
+			l.releaseTokenSemaphore(fingerprint)
+			return nil, err
+		}
+		releaseToken = func() {
+			ts.sem.Release(1)
+			l.releaseTokenSemaphore(fingerprint)
+		}
+	}
+	if l.global != nil {
+		if err := l.global.Acquire(ctx, 1); err != nil {
+			releaseToken()
+			return nil, err
+		}
+	}
+	atomic.AddInt64(&l.acquired, 1)
+	return func() {
+		if l.global != nil {
+			l.global.Release(1)
+		}
+		releaseToken()
+	}, nil
+}
+
+func (l *ConcurrencyLimiter) tokenSemaphore(fingerprint string) *tokenSemaphore {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	ts, ok := l.tokens[fingerprint]
+	if !ok {
+		ts = &tokenSemaphore{sem: semaphore.NewWeighted(l.perToken)}
+		l.tokens[fingerprint] = ts
+	}
+	ts.refs++
+	return ts
+}
+
+// releaseTokenSemaphore forgets the semaphore of the token nobody uses.
+func (l *ConcurrencyLimiter) releaseTokenSemaphore(fingerprint string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	ts := l.tokens[fingerprint]
+	ts.refs--
+	if ts.refs == 0 {
+		delete(l.tokens, fingerprint)
+	}
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	l := NewConcurrencyLimiter(1, 2)
+	ctx := context.Background()
+	mustAcquire := func(fingerprint string) func() {
+		t.Helper()
+		release, err := l.acquire(ctx, fingerprint)
+		if err != nil {
+			t.Fatalf("acquire(%q): %v", fingerprint, err)
+		}
+		return release
+	}
+	mustBlock := func(fingerprint string) {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(ctx, time.Millisecond*10)
+		defer cancel()
+		if release, err := l.acquire(ctx, fingerprint); err == nil {
+			release()
+			t.Fatalf("acquire(%q): expected to be blocked", fingerprint)
+		}
+	}
+
+	releaseA := mustAcquire("a")
+	mustBlock("a")
+	releaseB := mustAcquire("b")
+	mustBlock("c")
+	releaseA()
+	releaseC := mustAcquire("c")
+	releaseB()
+	releaseC()
+
+	stats := l.Stats()
+	if stats.Waiting != 0 {
+		t.Errorf("waiting: got=%d want=0", stats.Waiting)
+	}
+	if stats.Acquired != 3 {
+		t.Errorf("acquired: got=%d want=3", stats.Acquired)
+	}
+	if stats.TotalWait < time.Millisecond*20 {
+		t.Errorf("total wait: got=%s", stats.TotalWait)
+	}
+	if n := len(l.tokens); n != 0 {
+		t.Errorf("token semaphores are left: %d", n)
+	}
+}
+
+func TestConcurrencyLimiter_unlimited(t *testing.T) {
+	l := NewConcurrencyLimiter(0, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var releases []func()
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire(ctx, "a")
+		if err != nil {
+			t.Fatalf("acquire #%d: %v", i, err)
+		}
+		releases = append(releases, release)
+	}
+	for _, release := range releases {
+		release()
+	}
+	if n := len(l.tokens); n != 0 {
+		t.Errorf("token semaphores are left: %d", n)
+	}
+}
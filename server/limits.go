@@ -0,0 +1,184 @@
+package server
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	DefaultMaxComplexity = 5000
+	DefaultMaxDepth      = 10
+
+	CodeComplexityLimitExceeded = "COMPLEXITY_LIMIT_EXCEEDED"
+	CodeDepthLimitExceeded      = "DEPTH_LIMIT_EXCEEDED"
+)
+
+// queryLimits rejects operations whose complexity or depth exceeds the limits before they are executed.
+// A limit of zero or less is not enforced.
+type queryLimits struct {
+	maxComplexity int
+	maxDepth      int
+
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &queryLimits{}
+
+func (*queryLimits) ExtensionName() string {
+	return "QueryLimits"
+}
+
+func (l *queryLimits) Validate(schema graphql.ExecutableSchema) error {
+	l.es = schema
+	return nil
+}
+
+func (l *queryLimits) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	op := oc.Doc.Operations.ForName(oc.OperationName)
+	if op == nil {
+		return nil
+	}
+	if l.maxDepth > 0 {
+		if depth := selectionSetDepth(op.SelectionSet); depth > l.maxDepth {
+			err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, l.maxDepth)
+			errcode.Set(err, CodeDepthLimitExceeded)
+			err.Extensions["depth"] = depth
+			err.Extensions["maxDepth"] = l.maxDepth
+			return err
+		}
+	}
+	if l.maxComplexity > 0 {
+		if cost := operationComplexity(l.es, op, oc.Variables); cost > l.maxComplexity {
+			err := gqlerror.Errorf("operation has complexity %d, which exceeds the limit of %d", cost, l.maxComplexity)
+			errcode.Set(err, CodeComplexityLimitExceeded)
+			err.Extensions["complexity"] = cost
+			err.Extensions["maxComplexity"] = l.maxComplexity
+			return err
+		}
+	}
+	return nil
+}
+
+// selectionSetDepth returns how deep the fields are nested. Introspection fields are not counted.
+func selectionSetDepth(selectionSet ast.SelectionSet) int {
+	var depth int
+	for _, selection := range selectionSet {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionSetDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionSetDepth(s.Definition.SelectionSet)
+			}
+		case *ast.InlineFragment:
+			d = selectionSetDepth(s.SelectionSet)
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// operationComplexity is complexity.Calculate except that the complexity functions of the Relay connection fields
+// receive the complexity of the edges and the nodes only, and the other fields of the connections are added as is.
+// This lets the connection fields multiply the items by the page size without multiplying totalCount or pageInfo.
+func operationComplexity(es graphql.ExecutableSchema, op *ast.OperationDefinition, vars map[string]interface{}) int {
+	w := complexityWalker{es: es, schema: es.Schema(), vars: vars}
+	return w.selectionSetComplexity(op.SelectionSet)
+}
+
+type complexityWalker struct {
+	es     graphql.ExecutableSchema
+	schema *ast.Schema
+	vars   map[string]interface{}
+}
+
+func (w complexityWalker) selectionSetComplexity(selectionSet ast.SelectionSet) int {
+	child, _ := w.splitSelectionSetComplexity(selectionSet, false)
+	return child
+}
+
+// splitSelectionSetComplexity returns the complexity passed to the complexity function of the field, and the rest.
+// The rest is the fields other than the edges and the nodes if the selection set is of a connection, or zero otherwise.
+func (w complexityWalker) splitSelectionSetComplexity(selectionSet ast.SelectionSet, connection bool) (int, int) {
+	var child, rest int
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			def := w.schema.Types[s.Definition.Type.Name()]
+			if def.Name == "__Schema" {
+				continue
+			}
+			c := w.fieldComplexity(s, def)
+			if connection && s.Name != "edges" && s.Name != "nodes" {
+				rest = safeAdd(rest, c)
+			} else {
+				child = safeAdd(child, c)
+			}
+		case *ast.FragmentSpread:
+			c, r := w.splitSelectionSetComplexity(s.Definition.SelectionSet, connection)
+			child, rest = safeAdd(child, c), safeAdd(rest, r)
+		case *ast.InlineFragment:
+			c, r := w.splitSelectionSetComplexity(s.SelectionSet, connection)
+			child, rest = safeAdd(child, c), safeAdd(rest, r)
+		}
+	}
+	return child, rest
+}
+
+func (w complexityWalker) fieldComplexity(field *ast.Field, def *ast.Definition) int {
+	var childComplexity, rest int
+	switch def.Kind {
+	case ast.Object, ast.Interface, ast.Union:
+		childComplexity, rest = w.splitSelectionSetComplexity(field.SelectionSet, isConnection(def))
+	}
+	args := field.ArgumentMap(w.vars)
+	objects := []*ast.Definition{field.ObjectDefinition}
+	if field.ObjectDefinition.Kind == ast.Interface {
+		// interfaces have no complexity of their own, so the most expensive implementation is taken
+		objects = w.schema.GetPossibleTypes(field.ObjectDefinition)
+	}
+	var c int
+	for _, object := range objects {
+		oc := safeAdd(1, childComplexity)
+		if custom, ok := w.es.Complexity(object.Name, field.Name, childComplexity, args); ok && custom >= childComplexity {
+			oc = custom
+		}
+		if oc > c {
+			c = oc
+		}
+	}
+	return safeAdd(c, rest)
+}
+
+func isConnection(def *ast.Definition) bool {
+	return def.Kind == ast.Object && strings.HasSuffix(def.Name, "Connection") && (def.Fields.ForName("edges") != nil || def.Fields.ForName("nodes") != nil)
+}
+
+// safeAdd adds the complexities saturating at the max int and ignoring the negative ones as complexity.Calculate does.
+func safeAdd(a, b int) int {
+	if a < 0 {
+		a = 0
+	}
+	if b < 0 {
+		b = 0
+	}
+	if c := a + b; c >= a {
+		return c
+	}
+	return math.MaxInt
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestQueryLimits(t *testing.T) {
+	type testCase struct {
+		name          string
+		maxComplexity int
+		maxDepth      int
+		query         string
+		variables     string
+		wantErrors    []string
+		wantCodes     []string
+	}
+	testCases := []testCase{
+		{"within limits", 10, 2, `{ __typename }`, `{}`, nil, nil},
+		{
+			"artifacts multiplied by first",
+			1, 0,
+			`{ test__repository(owner: "a", name: "b") { artifacts(first: 100) { nodes { id name } } } }`, `{}`,
+			[]string{"operation has complexity 311, which exceeds the limit of 1"},
+			[]string{CodeComplexityLimitExceeded},
+		},
+		{
+			"artifacts with default page size",
+			1, 0,
+			`{ test__repository(owner: "a", name: "b") { artifacts { nodes { id } } } }`, `{}`,
+			[]string{"operation has complexity 71, which exceeds the limit of 1"},
+			[]string{CodeComplexityLimitExceeded},
+		},
+		{
+			"totalCount and pageInfo not multiplied",
+			1, 0,
+			`{ test__repository(owner: "a", name: "b") { artifacts(first: 100) { totalCount pageInfo { hasNextPage } ... on RepositoryArtifactConnection { nodes { id } } } } }`, `{}`,
+			[]string{"operation has complexity 214, which exceeds the limit of 1"},
+			[]string{CodeComplexityLimitExceeded},
+		},
+		{
+			"totalSizeInBytes",
+			1, 0,
+			`{ test__repository(owner: "a", name: "b") { artifacts(first: 1) { totalSizeInBytes } } }`, `{}`,
+			[]string{"operation has complexity 111, which exceeds the limit of 1"},
+			[]string{CodeComplexityLimitExceeded},
+		},
+		{
+			"REST calls",
+			1, 0,
+			`{ test__organization(login: "a") { billing { actions { totalMinutesUsed } storage { daysLeftInBillingCycle } } } }`, `{}`,
+			[]string{"operation has complexity 24, which exceeds the limit of 1"},
+			[]string{CodeComplexityLimitExceeded},
+		},
+		{
+			"entities multiplied by representations",
+			1, 0,
+			`query ($r: [_Any!]!) { _entities(representations: $r) { ... on Repository { artifacts(first: 100) { nodes { id } } } } }`,
+			`{"r":[{"__typename":"Repository","nameWithOwner":"a/b"},{"__typename":"Repository","nameWithOwner":"a/c"},{"__typename":"Repository","nameWithOwner":"a/d"}]}`,
+			[]string{"operation has complexity 630, which exceeds the limit of 1"},
+			[]string{CodeComplexityLimitExceeded},
+		},
+		{
+			"depth",
+			0, 5,
+			`{ test__organization(login: "a") { billing { actions { minutedUsedBreakdown { macOS { total } } } } } }`, `{}`,
+			[]string{"operation has depth 6, which exceeds the limit of 5"},
+			[]string{CodeDepthLimitExceeded},
+		},
+		{
+			"depth through fragments",
+			0, 3,
+			`query { test__organization(login: "a") { ...billing } } fragment billing on Organization { billing { actions { totalMinutesUsed } } }`, `{}`,
+			[]string{"operation has depth 4, which exceeds the limit of 3"},
+			[]string{CodeDepthLimitExceeded},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(map[string]interface{}{"query": tc.query, "variables": json.RawMessage(tc.variables)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(string(reqBody)))
+			req.Header.Set("content-type", "application/json")
+			rec := httptest.NewRecorder()
+			Handler(WithQueryLimits(tc.maxComplexity, tc.maxDepth)).ServeHTTP(rec, req)
+			var body struct {
+				Errors []struct {
+					Message    string
+					Extensions struct{ Code string }
+				}
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			var gotErrors, gotCodes []string
+			for _, e := range body.Errors {
+				gotErrors = append(gotErrors, e.Message)
+				gotCodes = append(gotCodes, e.Extensions.Code)
+			}
+			if diff := cmp.Diff(gotErrors, tc.wantErrors); diff != "" {
+				t.Errorf("errors (-got, +want):\n%s", diff)
+			}
+			if diff := cmp.Diff(gotCodes, tc.wantCodes); diff != "" {
+				t.Errorf("codes (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
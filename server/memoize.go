@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// memoizeTransport deduplicates identical GET requests.
+//
+// It is meant to be scoped to a single GraphQL operation so resolvers asking for the same resource share one upstream call.
+// Only successful responses are remembered; the failed ones and the transport errors are shared with the concurrent callers but retried by the later ones.
+type memoizeTransport struct {
+	base  http.RoundTripper
+	group singleflight.Group
+	mux   sync.Mutex
+	cache map[string]*memoizedResponse
+}
+
+var _ http.RoundTripper = (*memoizeTransport)(nil)
+
+func newMemoizeTransport(base http.RoundTripper) *memoizeTransport {
+	return &memoizeTransport{base: base, cache: map[string]*memoizedResponse{}}
+}
+
+func (t *memoizeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Method != http.MethodGet {
+		return t.base.RoundTrip(r)
+	}
+	key := memoizeKey(r)
+	t.mux.Lock()
+	cached, ok := t.cache[key]
+	t.mux.Unlock()
+	if ok {
+		return cached.response(r), nil
+	}
+	ch := t.group.DoChan(key, func() (any, error) {
+		ctx, cancel := detachedContext(r.Context())
+		defer cancel()
+		resp, err := t.base.RoundTrip(r.WithContext(ctx))
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		m := &memoizedResponse{
+			status:     resp.Status,
+			statusCode: resp.StatusCode,
+			proto:      resp.Proto,
+			protoMajor: resp.ProtoMajor,
+			protoMinor: resp.ProtoMinor,
+			header:     resp.Header.Clone(),
+			body:       body,
+		}
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			t.mux.Lock()
+			t.cache[key] = m
+			t.mux.Unlock()
+		}
+		return m, nil
+	})
+	select {
+	case <-r.Context().Done():
+		return nil, r.Context().Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*memoizedResponse).response(r), nil
+	}
+}
+
+// detachedContext returns the context that is not canceled along with the parent but keeps its deadline,
+// so that the shared call outlives the caller who started it yet still ends with the operation.
+func detachedContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx := context.WithoutCancel(parent)
+	if deadline, ok := parent.Deadline(); ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+func memoizeKey(r *http.Request) string {
+	return r.Method + " " + r.URL.String() + " " + r.Header.Get("accept")
+}
+
+type memoizedResponse struct {
+	status     string
+	statusCode int
+	proto      string
+	protoMajor int
+	protoMinor int
+	header     http.Header
+	body       []byte
+}
+
+func (m *memoizedResponse) response(r *http.Request) *http.Response {
+	return &http.Response{
+		Status:        m.status,
+		StatusCode:    m.statusCode,
+		Proto:         m.proto,
+		ProtoMajor:    m.protoMajor,
+		ProtoMinor:    m.protoMinor,
+		Header:        m.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(m.body)),
+		ContentLength: int64(len(m.body)),
+		Request:       r,
+	}
+}
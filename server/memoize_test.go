@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoizeTransport(t *testing.T) {
+	type testCase struct {
+		name      string
+		method    string
+		paths     []string
+		wantCalls int64
+	}
+	testCases := []testCase{
+		{"same GETs", http.MethodGet, []string{"/orgs/a", "/orgs/a", "/orgs/a"}, 1},
+		{"different GETs", http.MethodGet, []string{"/orgs/a", "/orgs/b", "/orgs/a"}, 2},
+		{"POSTs are not memoized", http.MethodPost, []string{"/orgs/a", "/orgs/a"}, 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt64(&calls, 1)
+				_, _ = io.WriteString(w, r.URL.Path)
+			}))
+			defer srv.Close()
+			client := &http.Client{Transport: newMemoizeTransport(srv.Client().Transport)}
+			var wg sync.WaitGroup
+			for _, path := range tc.paths {
+				path := path
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					req, err := http.NewRequestWithContext(context.Background(), tc.method, srv.URL+path, nil)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					resp, err := client.Do(req)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					defer resp.Body.Close()
+					body, err := io.ReadAll(resp.Body)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if string(body) != path {
+						t.Errorf("body: got=%q want=%q", string(body), path)
+					}
+				}()
+			}
+			wg.Wait()
+			if calls != tc.wantCalls {
+				t.Errorf("upstream calls: got=%d want=%d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestMemoizeTransport_failures(t *testing.T) {
+	type testCase struct {
+		name      string
+		status    int
+		wantCalls int64
+	}
+	testCases := []testCase{
+		{"successes are memoized", http.StatusOK, 1},
+		{"client errors are not memoized", http.StatusNotFound, 2},
+		{"server errors are not memoized", http.StatusBadGateway, 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt64(&calls, 1)
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+			client := &http.Client{Transport: newMemoizeTransport(srv.Client().Transport)}
+			for i := 0; i < 2; i++ {
+				req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, srv.URL+"/orgs/a", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp, err := client.Do(req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tc.status {
+					t.Errorf("status: got=%d want=%d", resp.StatusCode, tc.status)
+				}
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("upstream calls: got=%d want=%d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestMemoizeTransport_callerCanceled(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls int64
+	var deadline time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&calls, 1)
+		close(started)
+		<-release
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		deadline, _ = r.Context().Deadline()
+		return srv.Client().Transport.RoundTrip(r)
+	})
+	client := &http.Client{Transport: newMemoizeTransport(base)}
+
+	opDeadline := time.Now().Add(time.Minute)
+	opCtx, cancelOp := context.WithDeadline(context.Background(), opDeadline)
+	defer cancelOp()
+	firstCtx, cancelFirst := context.WithCancel(opCtx)
+	firstErr := make(chan error, 1)
+	go func() {
+		req, _ := http.NewRequestWithContext(firstCtx, http.MethodGet, srv.URL+"/orgs/a", nil)
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		firstErr <- err
+	}()
+	<-started
+	secondBody := make(chan string, 1)
+	go func() {
+		req, _ := http.NewRequestWithContext(opCtx, http.MethodGet, srv.URL+"/orgs/a", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Error(err)
+			secondBody <- ""
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		secondBody <- string(body)
+	}()
+	cancelFirst()
+	if err := <-firstErr; err == nil {
+		t.Error("the canceled caller is expected to fail")
+	}
+	close(release)
+	if got := <-secondBody; got != "ok" {
+		t.Errorf("body: got=%q want=%q", got, "ok")
+	}
+	if calls != 1 {
+		t.Errorf("upstream calls: got=%d want=1", calls)
+	}
+	if !deadline.Equal(opDeadline) {
+		t.Errorf("deadline of the shared call: got=%s want=%s", deadline, opDeadline)
+	}
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "github_graphql_proxy"
+
+// registerMetrics exposes the stats of the concurrency limiter and the response cache.
+func registerMetrics(o *options) {
+	var cs []prometheus.Collector
+	if limiter := o.limiter; limiter != nil {
+		cs = append(cs,
+			prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "upstream_concurrency_waiting",
+				Help:      "The number of upstream calls waiting for a concurrency slot.",
+			}, func() float64 { return float64(limiter.Stats().Waiting) }),
+			prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "upstream_concurrency_wait_seconds_total",
+				Help:      "The accumulated time upstream calls waited for a concurrency slot.",
+			}, func() float64 { return limiter.Stats().TotalWait.Seconds() }),
+		)
+	}
+	if cache := o.cache; cache != nil {
+		cs = append(cs,
+			prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "response_cache_hits_total",
+				Help:      "The number of upstream responses served from the cache without calling GitHub.",
+			}, func() float64 { return float64(cache.Stats().Hits) }),
+			prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "response_cache_revalidations_total",
+				Help:      "The number of cached upstream responses revalidated by 304 Not Modified.",
+			}, func() float64 { return float64(cache.Stats().Revalidations) }),
+			prometheus.NewCounterFunc(prometheus.CounterOpts{
+				Namespace: metricsNamespace,
+				Name:      "response_cache_misses_total",
+				Help:      "The number of upstream responses not found in the cache.",
+			}, func() float64 { return float64(cache.Stats().Misses) }),
+		)
+	}
+	for _, c := range cs {
+		if err := o.metrics.Register(c); err != nil {
+			var alreadyRegistered prometheus.AlreadyRegisteredError
+			if !errors.As(err, &alreadyRegistered) {
+				panic(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package server
+
+import (
+	"log/slog"
+	"net/netip"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/aereal/github-graphql-proxy/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultPersistedQueryCacheSize is the default number of queries kept for automatic persisted queries.
+const DefaultPersistedQueryCacheSize = 1000
+
+// Option configures Handler and Start.
+type Option func(*options)
+
+type options struct {
+	cache               *ResponseCache
+	limiter             *ConcurrencyLimiter
+	retryMaxAttempts    int
+	retryMaxWait        time.Duration
+	app                 *authz.App
+	appAuthMode         authz.AppAuthMode
+	apiBaseURL          string
+	uploadURL           string
+	tracerProvider      trace.TracerProvider
+	metrics             *metrics.Metrics
+	logger              *slog.Logger
+	drainDelay          time.Duration
+	readinessToken      string
+	health              *health
+	playground          bool
+	introspection       bool
+	persistedQueries    graphql.Cache
+	maxComplexity       int
+	maxDepth            int
+	resolverConcurrency int64
+	production          bool
+	trustedCallers      []netip.Prefix
+	serviceSecret       string
+	policy              *authz.Policy
+	strictAuth          bool
+	strictAuthAllowsApp bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		cache:               NewResponseCache(DefaultCacheSize, 0),
+		limiter:             NewConcurrencyLimiter(DefaultMaxConcurrencyPerToken(), DefaultMaxConcurrency),
+		retryMaxAttempts:    DefaultRetryMaxAttempts,
+		retryMaxWait:        DefaultRetryMaxWait,
+		tracerProvider:      otel.GetTracerProvider(),
+		metrics:             metrics.New(),
+		logger:              slog.Default(),
+		drainDelay:          DefaultDrainDelay,
+		playground:          true,
+		introspection:       true,
+		persistedQueries:    lru.New(DefaultPersistedQueryCacheSize),
+		maxComplexity:       DefaultMaxComplexity,
+		maxDepth:            DefaultMaxDepth,
+		resolverConcurrency: DefaultResolverConcurrency,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	o.health = newHealth(o)
+	return o
+}
+
+// WithResponseCache makes the handler share the given cache. Passing nil disables caching.
+func WithResponseCache(cache *ResponseCache) Option {
+	return func(o *options) { o.cache = cache }
+}
+
+// WithCache configures the size and TTL of the response cache. A size of zero or less disables caching.
+func WithCache(size int, ttl time.Duration) Option {
+	return func(o *options) {
+		if size <= 0 {
+			o.cache = nil
+			return
+		}
+		o.cache = NewResponseCache(size, ttl)
+	}
+}
+
+// WithConcurrencyLimiter makes the handler share the given limiter.
+func WithConcurrencyLimiter(limiter *ConcurrencyLimiter) Option {
+	return func(o *options) { o.limiter = limiter }
+}
+
+// WithConcurrency configures how many upstream calls can run concurrently for each token and across the process.
+// Zero or less means unlimited.
+func WithConcurrency(perToken, global int64) Option {
+	return func(o *options) { o.limiter = NewConcurrencyLimiter(perToken, global) }
+}
+
+// WithRetry configures how many times and how long upstream calls are retried. A maxAttempts of one or less disables retries.
+func WithRetry(maxAttempts int, maxWait time.Duration) Option {
+	return func(o *options) {
+		o.retryMaxAttempts = maxAttempts
+		o.retryMaxWait = maxWait
+	}
+}
+
+// WithGitHubApp makes the handler call GitHub as the installation of the App according to the mode.
+func WithGitHubApp(app *authz.App, mode authz.AppAuthMode) Option {
+	return func(o *options) {
+		o.app = app
+		o.appAuthMode = mode
+	}
+}
+
+// WithGitHubEnterprise makes the handler call GitHub Enterprise Server at the URLs instead of GitHub.com.
+func WithGitHubEnterprise(apiBaseURL, uploadURL string) Option {
+	return func(o *options) {
+		o.apiBaseURL = apiBaseURL
+		o.uploadURL = uploadURL
+	}
+}
+
+// WithTracerProvider makes the handler trace operations, resolvers and upstream calls with the provider instead of the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) { o.tracerProvider = tp }
+}
+
+// WithMetrics makes the handler record metrics to m and serve them at /metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(o *options) { o.metrics = m }
+}
+
+// WithLogger makes the handler write server and access logs to the logger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// WithDrainDelay configures how long the server keeps serving after the readiness starts failing on shutdown.
+func WithDrainDelay(d time.Duration) Option {
+	return func(o *options) { o.drainDelay = d }
+}
+
+// WithReadinessCheck makes the readiness probe check GitHub is reachable with the token.
+func WithReadinessCheck(token string) Option {
+	return func(o *options) { o.readinessToken = token }
+}
+
+// WithPlayground configures whether the GraphQL playground is served at /.
+func WithPlayground(enabled bool) Option {
+	return func(o *options) { o.playground = enabled }
+}
+
+// WithIntrospection configures whether the introspection queries are allowed.
+func WithIntrospection(enabled bool) Option {
+	return func(o *options) { o.introspection = enabled }
+}
+
+// WithPersistedQueryStore makes the handler keep the queries of automatic persisted queries in the store,
+// which can be shared across processes. Passing nil disables automatic persisted queries.
+func WithPersistedQueryStore(store graphql.Cache) Option {
+	return func(o *options) { o.persistedQueries = store }
+}
+
+// WithPersistedQueries configures the size of the in-memory LRU store of automatic persisted queries. A size of zero or less disables them.
+func WithPersistedQueries(size int) Option {
+	return func(o *options) {
+		if size <= 0 {
+			o.persistedQueries = nil
+			return
+		}
+		o.persistedQueries = lru.New(size)
+	}
+}
+
+// WithQueryLimits configures the max complexity and depth of operations. A limit of zero or less disables it.
+func WithQueryLimits(maxComplexity, maxDepth int) Option {
+	return func(o *options) {
+		o.maxComplexity = maxComplexity
+		o.maxDepth = maxDepth
+	}
+}
+
+// WithResolverConcurrency configures how many resolvers can run concurrently in an operation. Zero or less means unlimited.
+func WithResolverConcurrency(n int64) Option {
+	return func(o *options) { o.resolverConcurrency = n }
+}
+
+// WithStrictAuth rejects GraphQL requests without any credential with 401 instead of calling GitHub anonymously.
+// The trusted callers in production mode can still query _service without a credential.
+func WithStrictAuth(enabled bool) Option {
+	return func(o *options) { o.strictAuth = enabled }
+}
+
+// WithStrictAuthAllowingApp makes strict mode accept the requests without a credential that the GitHub App authenticates in fallback mode.
+// Without it, strict mode requires the caller's credential even if the App is configured.
+func WithStrictAuthAllowingApp(allowed bool) Option {
+	return func(o *options) { o.strictAuthAllowsApp = allowed }
+}
+
+// WithPolicy makes the resolvers ask the policy before calling GitHub.
+func WithPolicy(policy *authz.Policy) Option {
+	return func(o *options) { o.policy = policy }
+}
+
+// WithProductionMode disables the playground and introspection, and allows _service and /metrics only for the callers
+// from the trusted networks or with the shared secret in ServiceSecretHeader.
+func WithProductionMode(trustedCallers []netip.Prefix, serviceSecret string) Option {
+	return func(o *options) {
+		o.production = true
+		o.playground = false
+		o.introspection = false
+		o.trustedCallers = trustedCallers
+		o.serviceSecret = serviceSecret
+	}
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	ModeDevelopment = "development"
+	ModeProduction  = "production"
+
+	// ServiceSecretHeader is the request header that carries the shared secret to query _service in production mode.
+	ServiceSecretHeader = "X-Proxy-Service-Secret"
+)
+
+var (
+	ErrUnknownMode          = errors.New("unknown mode")
+	ErrInvalidTrustedCaller = errors.New("invalid trusted caller")
+)
+
+// ParseTrustedCaller parses an IP address or a CIDR such as 10.0.0.0/8.
+func ParseTrustedCaller(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("%w: %q", ErrInvalidTrustedCaller, s)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("%w: %q", ErrInvalidTrustedCaller, s)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+type trustedCallerCtxKey struct{}
+
+func withTrustedCaller(ctx context.Context, trusted bool) context.Context {
+	return context.WithValue(ctx, trustedCallerCtxKey{}, trusted)
+}
+
+func isTrustedCaller(ctx context.Context) bool {
+	v, _ := ctx.Value(trustedCallerCtxKey{}).(bool)
+	return v
+}
+
+// trustsCaller tells whether the request comes from the trusted networks or carries the shared secret.
+//
+// It looks at the peer address only because X-Forwarded-For can be forged by anyone.
+func (o *options) trustsCaller(r *http.Request) bool {
+	if o.serviceSecret != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(ServiceSecretHeader)), []byte(o.serviceSecret)) == 1 {
+		return true
+	}
+	if len(o.trustedCallers) == 0 {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range o.trustedCallers {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyTrustedCallers responds 403 Forbidden to the callers not trusted.
+func (o *options) onlyTrustedCallers(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !o.trustsCaller(r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// serviceGuard rejects the operations querying _service from the callers not trusted.
+type serviceGuard struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = serviceGuard{}
+
+func (serviceGuard) ExtensionName() string {
+	return "ServiceGuard"
+}
+
+func (serviceGuard) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (serviceGuard) MutateOperationContext(ctx context.Context, oc *graphql.OperationContext) *gqlerror.Error {
+	op := oc.Doc.Operations.ForName(oc.OperationName)
+	if op == nil || !selectsField(op.SelectionSet, "_service") {
+		return nil
+	}
+	if !isTrustedCaller(ctx) {
+		err := gqlerror.Errorf("_service is available only for trusted callers")
+		errcode.Set(err, resolvers.CodeForbidden)
+		return err
+	}
+	// The generated _service resolver refuses to serve while introspection is disabled,
+	// so enable it unless the operation also asks for the general introspection.
+	if !selectsField(op.SelectionSet, "__schema") && !selectsField(op.SelectionSet, "__type") {
+		oc.DisableIntrospection = false
+	}
+	return nil
+}
+
+func selectsField(selectionSet ast.SelectionSet, name string) bool {
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name == name {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if s.Definition != nil && selectsField(s.Definition.SelectionSet, name) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectsField(s.SelectionSet, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProductionMode(t *testing.T) {
+	type testCase struct {
+		name       string
+		opts       []Option
+		query      string
+		remoteAddr string
+		secret     string
+		wantData   string
+		wantErrors []string
+	}
+	production := WithProductionMode([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, "s3cret")
+	testCases := []testCase{
+		{"development", nil, `{ _service { sdl } }`, "203.0.113.1:1234", "", `{"_service":{"sdl":"`, nil},
+		{"development introspection", nil, `{ __schema { queryType { name } } }`, "203.0.113.1:1234", "", `{"__schema":{"queryType":{"name":"Query"}}}`, nil},
+		{"untrusted", []Option{production}, `{ _service { sdl } }`, "203.0.113.1:1234", "", "null", []string{"_service is available only for trusted callers"}},
+		{"untrusted via fragment", []Option{production}, `query { ...service } fragment service on Query { _service { sdl } }`, "203.0.113.1:1234", "", "null", []string{"_service is available only for trusted callers"}},
+		{"wrong secret", []Option{production}, `{ _service { sdl } }`, "203.0.113.1:1234", "secret", "null", []string{"_service is available only for trusted callers"}},
+		{"trusted network", []Option{production}, `{ _service { sdl } }`, "10.1.2.3:1234", "", `{"_service":{"sdl":"`, nil},
+		{"trusted IPv4-mapped address", []Option{production}, `{ _service { sdl } }`, "[::ffff:10.1.2.3]:1234", "", `{"_service":{"sdl":"`, nil},
+		{"shared secret", []Option{production}, `{ _service { sdl } }`, "203.0.113.1:1234", "s3cret", `{"_service":{"sdl":"`, nil},
+		{"introspection", []Option{production}, `{ __schema { queryType { name } } }`, "10.1.2.3:1234", "s3cret", `{"__schema":null}`, []string{"introspection disabled"}},
+		{"introspection with _service", []Option{production}, `{ _service { sdl } __type(name: "Query") { name } }`, "10.1.2.3:1234", "", "null", []string{"federated introspection disabled", "introspection disabled"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(map[string]string{"query": tc.query})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(string(reqBody)))
+			req.Header.Set("content-type", "application/json")
+			req.RemoteAddr = tc.remoteAddr
+			if tc.secret != "" {
+				req.Header.Set(ServiceSecretHeader, tc.secret)
+			}
+			rec := httptest.NewRecorder()
+			Handler(tc.opts...).ServeHTTP(rec, req)
+			var body struct {
+				Data   json.RawMessage
+				Errors []struct{ Message string }
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(body.Data); !strings.HasPrefix(got, tc.wantData) {
+				t.Errorf("data: got=%s want prefix=%s", got, tc.wantData)
+			}
+			var gotErrors []string
+			for _, e := range body.Errors {
+				gotErrors = append(gotErrors, e.Message)
+			}
+			sort.Strings(gotErrors)
+			if diff := cmp.Diff(gotErrors, tc.wantErrors); diff != "" {
+				t.Errorf("errors (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProductionMode_metrics(t *testing.T) {
+	type testCase struct {
+		name       string
+		opts       []Option
+		remoteAddr string
+		secret     string
+		wantStatus int
+	}
+	production := WithProductionMode([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}, "s3cret")
+	testCases := []testCase{
+		{"development", nil, "203.0.113.1:1234", "", http.StatusOK},
+		{"untrusted", []Option{production}, "203.0.113.1:1234", "", http.StatusForbidden},
+		{"trusted network", []Option{production}, "10.1.2.3:1234", "", http.StatusOK},
+		{"shared secret", []Option{production}, "203.0.113.1:1234", "s3cret", http.StatusOK},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.RemoteAddr = tc.remoteAddr
+			if tc.secret != "" {
+				req.Header.Set(ServiceSecretHeader, tc.secret)
+			}
+			rec := httptest.NewRecorder()
+			Handler(tc.opts...).ServeHTTP(rec, req)
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status code: got=%d want=%d", rec.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestProductionMode_playground(t *testing.T) {
+	type testCase struct {
+		name       string
+		opts       []Option
+		wantStatus int
+	}
+	testCases := []testCase{
+		{"development", nil, http.StatusOK},
+		{"production", []Option{WithProductionMode(nil, "")}, http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler(tc.opts...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status code: got=%d want=%d", rec.Code, tc.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryMaxWait     = time.Second * 10
+
+	retryBaseDelay = time.Millisecond * 500
+)
+
+// retryTransport retries idempotent requests failed by secondary rate limits, exhausted rate limits or transient server errors.
+type retryTransport struct {
+	base        http.RoundTripper
+	maxAttempts int
+	maxWait     time.Duration
+	baseDelay   time.Duration
+}
+
+var _ http.RoundTripper = (*retryTransport)(nil)
+
+func (t *retryTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !isIdempotent(r.Method) || (r.Body != nil && r.Body != http.NoBody && r.GetBody == nil) {
+		return t.base.RoundTrip(r)
+	}
+	ctx := r.Context()
+	for attempt := 1; ; attempt++ {
+		req := r
+		if attempt > 1 && r.GetBody != nil {
+			body, err := r.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = r.Clone(ctx)
+			req.Body = body
+		}
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.maxAttempts {
+			return resp, err
+		}
+		wait, retryable := t.retryAfter(attempt, resp, err)
+		if !retryable || wait > t.maxWait || exceedsDeadline(ctx, wait) {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *retryTransport) retryAfter(attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+		return t.backoff(attempt), true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if wait, ok := parseRetryAfter(resp.Header); ok {
+			return wait, true
+		}
+		return t.backoff(attempt), true
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if wait, ok := parseRetryAfter(resp.Header); ok {
+			return wait, true
+		}
+		if resp.Header.Get("x-ratelimit-remaining") == "0" {
+			if reset, err := strconv.ParseInt(resp.Header.Get("x-ratelimit-reset"), 10, 64); err == nil {
+				wait := time.Until(time.Unix(reset, 0))
+				if wait < 0 {
+					wait = 0
+				}
+				return wait, true
+			}
+		}
+		if isSecondaryRateLimit(resp) {
+			return t.backoff(attempt), true
+		}
+	}
+	return 0, false
+}
+
+// backoff returns the jittered exponential backoff of the attempt.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	max := t.baseDelay << (attempt - 1)
+	if max <= 0 || max > t.maxWait {
+		max = t.maxWait
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	msg := strings.ToLower(string(body))
+	return strings.Contains(msg, "secondary rate limit") || strings.Contains(msg, "abuse detection")
+}
+
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	v := header.Get("retry-after")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		wait := time.Until(at)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+func exceedsDeadline(ctx context.Context, wait time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Now().Add(wait).After(deadline)
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	type response struct {
+		code   int
+		header http.Header
+		body   string
+	}
+	type testCase struct {
+		name      string
+		method    string
+		timeout   time.Duration
+		responses []response
+		wantCalls int64
+		wantCode  int
+	}
+	testCases := []testCase{
+		{
+			"recover from 503",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusServiceUnavailable}, {code: http.StatusOK}},
+			2, http.StatusOK,
+		},
+		{
+			"give up after max attempts",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusBadGateway}, {code: http.StatusBadGateway}, {code: http.StatusBadGateway}},
+			3, http.StatusBadGateway,
+		},
+		{
+			"secondary rate limit with Retry-After",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusForbidden, header: http.Header{"Retry-After": {"0"}}}, {code: http.StatusOK}},
+			2, http.StatusOK,
+		},
+		{
+			"secondary rate limit message",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusForbidden, body: `{"message":"You have exceeded a secondary rate limit."}`}, {code: http.StatusOK}},
+			2, http.StatusOK,
+		},
+		{
+			"rate limit exhausted",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusForbidden, header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1"}}}, {code: http.StatusOK}},
+			2, http.StatusOK,
+		},
+		{
+			"Retry-After exceeds max wait",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusForbidden, header: http.Header{"Retry-After": {"60"}}}, {code: http.StatusOK}},
+			1, http.StatusForbidden,
+		},
+		{
+			"Retry-After exceeds deadline",
+			http.MethodGet, time.Millisecond * 500,
+			[]response{{code: http.StatusServiceUnavailable, header: http.Header{"Retry-After": {"1"}}}, {code: http.StatusOK}},
+			1, http.StatusServiceUnavailable,
+		},
+		{
+			"permission error",
+			http.MethodGet, 0,
+			[]response{{code: http.StatusForbidden, body: `{"message":"Must have admin rights to Repository."}`}, {code: http.StatusOK}},
+			1, http.StatusForbidden,
+		},
+		{
+			"not idempotent",
+			http.MethodPost, 0,
+			[]response{{code: http.StatusServiceUnavailable}, {code: http.StatusOK}},
+			1, http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt64(&calls, 1) - 1
+				resp := tc.responses[i]
+				for k, vs := range resp.header {
+					w.Header()[k] = vs
+				}
+				w.WriteHeader(resp.code)
+				_, _ = io.WriteString(w, resp.body)
+			}))
+			defer srv.Close()
+			client := &http.Client{Transport: &retryTransport{base: srv.Client().Transport, maxAttempts: 3, maxWait: time.Second * 2, baseDelay: time.Millisecond}}
+			ctx := context.Background()
+			if tc.timeout > 0 {
+				var cancel func()
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+			req, err := http.NewRequestWithContext(ctx, tc.method, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantCode {
+				t.Errorf("status code: got=%d want=%d", resp.StatusCode, tc.wantCode)
+			}
+			if calls != tc.wantCalls {
+				t.Errorf("upstream calls: got=%d want=%d", calls, tc.wantCalls)
+			}
+		})
+	}
+}
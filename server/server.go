@@ -4,11 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
-	"runtime"
 	"syscall"
 	"time"
 
@@ -18,82 +17,174 @@ import (
 	"github.com/99designs/gqlgen/graphql/playground"
 	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
 	"github.com/aereal/github-graphql-proxy/authz"
+	"github.com/aereal/github-graphql-proxy/federatedtracing"
+	"github.com/aereal/github-graphql-proxy/metrics"
+	"github.com/aereal/github-graphql-proxy/ratelimit"
 	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/aereal/github-graphql-proxy/tracing"
 	"github.com/google/go-github/v47/github"
-	"golang.org/x/sync/semaphore"
 )
 
-func Handler() http.Handler {
+func Handler(opts ...Option) http.Handler {
+	return newHandler(newOptions(opts))
+}
+
+func newHandler(o *options) http.Handler {
+	registerMetrics(o)
 	mux := http.NewServeMux()
-	mux.Handle("/", playground.Handler("GraphQL playground", "/extension/query"))
-	mux.Handle("/extension/query", withSemaphoreClient(int64(runtime.GOMAXPROCS(0))))
+	metricsHandler := o.metrics.Handler()
+	if o.production {
+		metricsHandler = o.onlyTrustedCallers(metricsHandler)
+	}
+	mux.Handle("/metrics", metricsHandler)
+	mux.Handle("/healthz", o.health.liveness())
+	mux.Handle("/readyz", o.health.readiness())
+	if o.playground {
+		mux.Handle("/", playground.Handler("GraphQL playground", "/extension/query"))
+	}
+	mux.Handle("/extension/query", tracing.Handler(withSemaphoreClient(o), "extension/query", o.tracerProvider))
 	return mux
 }
 
-func withSemaphoreClient(maxConcurrency int64) http.Handler {
+func withSemaphoreClient(o *options) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		httpClient := authz.ProxiedHTTPClient(r.Context(), r.Header.Get("authorization"))
-		rt := &semaphoreTransport{
-			base: httpClient.Transport,
-			sem:  semaphore.NewWeighted(maxConcurrency),
+		authzHeader := r.Header.Get("authorization")
+		authenticated := o.authenticates(authzHeader)
+		if o.strictAuth && !authenticated && !(o.production && o.trustsCaller(r)) {
+			accessLog(o.logger, authz.AnonymousFingerprint, http.HandlerFunc(writeUnauthenticated)).ServeHTTP(w, r)
+			return
+		}
+		fingerprint := authz.Fingerprint(authzHeader)
+		tokenType := authz.TokenTypeOf(authzHeader)
+		var base http.RoundTripper
+		if o.app != nil && o.appAuthMode.Uses(authzHeader) {
+			base = o.app.Transport(http.DefaultTransport)
+			fingerprint = fmt.Sprintf("app:%d", o.app.ID())
+			tokenType = authz.TokenTypeInstallation
+		} else {
+			base = authz.ProxiedHTTPClient(r.Context(), authzHeader).Transport
+		}
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		var rt http.RoundTripper = &semaphoreTransport{
+			base:        tracing.Transport(o.metrics.Transport(base, fingerprint), o.tracerProvider),
+			limiter:     o.limiter,
+			fingerprint: fingerprint,
+		}
+		if o.retryMaxAttempts > 1 {
+			rt = &retryTransport{base: rt, maxAttempts: o.retryMaxAttempts, maxWait: o.retryMaxWait, baseDelay: retryBaseDelay}
+		}
+		if o.cache != nil {
+			rt = o.cache.transport(fingerprint, rt)
+		}
+		rt = newMemoizeTransport(&ratelimit.Transport{Base: rt})
+		githubClient, err := o.newGitHubClient(&http.Client{Transport: rt})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		if rt.base == nil {
-			rt.base = http.DefaultTransport
+		r = r.WithContext(authz.WithTokenType(r.Context(), tokenType))
+		if o.production {
+			r = r.WithContext(withTrustedCaller(r.Context(), o.trustsCaller(r)))
 		}
-		httpClient.Transport = rt
-		h := queryHandler(github.NewClient(httpClient))
-		h.ServeHTTP(w, r)
+		h := queryHandler(githubClient, o)
+		if o.strictAuth && !authenticated {
+			h = serviceOnly(h)
+		}
+		h = cacheControl(h, o.cache.maxAge())
+		accessLog(o.logger, fingerprint, h).ServeHTTP(w, r)
 	})
 }
 
-func Start(ctx context.Context, addr string, startTimeout time.Duration) error {
+func Start(ctx context.Context, addr string, startTimeout time.Duration, opts ...Option) error {
+	checkCtx, cancel := context.WithTimeout(ctx, upstreamCheckTimeout)
+	defer cancel()
+	o := newOptions(opts)
+	upstream, err := detectUpstream(checkCtx, o)
+	if err != nil {
+		return err
+	}
+	o.logger.Info("detected upstream", slog.String("upstream", upstream.String()))
 	srv := &http.Server{
-		Handler: Handler(),
+		Handler: newHandler(o),
 		Addr:    addr,
 	}
-	go graceful(ctx, srv, startTimeout)
-	log.Printf("starting server (addr=%s) ...", addr)
-	err := srv.ListenAndServe()
+	go graceful(ctx, srv, startTimeout, o)
+	o.logger.Info("starting server", slog.String("addr", addr))
+	err = srv.ListenAndServe()
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
 	return nil
 }
 
-func graceful(ctx context.Context, srv *http.Server, timeout time.Duration) {
+func graceful(ctx context.Context, srv *http.Server, timeout time.Duration, o *options) {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	sig := <-sigChan
+	o.health.drain()
+	o.logger.Info("draining server", slog.String("signal", sig.String()), slog.Duration("drain_delay", o.drainDelay))
+	select {
+	case <-time.After(o.drainDelay):
+	case <-ctx.Done():
+	}
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
-	log.Printf("shutting down server (%v) ...", sig)
+	o.logger.Info("shutting down server")
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("failed to shutdown: %v", err)
+		o.logger.Error("failed to shutdown", slog.String("error", err.Error()))
 	}
 }
 
 type semaphoreTransport struct {
-	base http.RoundTripper
-	sem  *semaphore.Weighted
+	base        http.RoundTripper
+	limiter     *ConcurrencyLimiter
+	fingerprint string
 }
 
 var _ http.RoundTripper = (*semaphoreTransport)(nil)
 
 func (t *semaphoreTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	ctx := r.Context()
-	if err := t.sem.Acquire(ctx, 1); err != nil {
+	release, err := t.limiter.acquire(ctx, t.fingerprint)
+	if err != nil {
 		return nil, fmt.Errorf("request cancelled: %w", err)
 	}
-	defer t.sem.Release(1)
+	defer release()
 	return t.base.RoundTrip(r)
 }
 
-func queryHandler(githubClient *github.Client) http.Handler {
-	schema := githubgraphqlproxy.NewExecutableSchema(githubgraphqlproxy.Config{Resolvers: resolvers.New(githubClient)})
+func queryHandler(githubClient *github.Client, o *options) http.Handler {
+	schema := githubgraphqlproxy.NewExecutableSchema(githubgraphqlproxy.Config{
+		Resolvers:  resolvers.New(githubClient, resolvers.WithPolicy(o.policy)),
+		Complexity: githubgraphqlproxy.NewComplexityRoot(),
+	})
 	h := handler.New(schema)
 	h.AddTransport(transport.Options{ /* TODO: AllowedMethods */ })
 	h.AddTransport(transport.GET{})
 	h.AddTransport(transport.POST{})
-	h.Use(extension.Introspection{})
-	return h
+	if o.introspection {
+		h.Use(extension.Introspection{})
+	}
+	if o.production {
+		h.Use(serviceGuard{})
+	}
+	if o.strictAuth {
+		h.Use(serviceOnlyGuard{})
+	}
+	if o.persistedQueries != nil {
+		h.Use(extension.AutomaticPersistedQuery{Cache: o.persistedQueries})
+	}
+	h.Use(&queryLimits{maxComplexity: o.maxComplexity, maxDepth: o.maxDepth})
+	h.Use(resolverFanOutLimit{max: o.resolverConcurrency})
+	h.Use(cacheControlExtension{})
+	h.Use(resolvers.Extension{})
+	h.Use(ratelimit.Extension{})
+	h.Use(accessLogExtension{})
+	h.Use(tracing.Extension{TracerProvider: o.tracerProvider})
+	h.Use(metrics.Extension{Metrics: o.metrics})
+	h.Use(federatedtracing.Extension{})
+	h.SetErrorPresenter(resolvers.ErrorPresenter)
+	return federatedtracing.Handler(h)
 }
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHandler_metrics(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code: got=%d", rec.Code)
+	}
+	body := rec.Body.String()
+	for _, name := range []string{
+		"github_graphql_proxy_upstream_concurrency_waiting",
+		"github_graphql_proxy_upstream_concurrency_wait_seconds_total",
+		"github_graphql_proxy_response_cache_hits_total",
+		"github_graphql_proxy_response_cache_revalidations_total",
+	} {
+		if !strings.Contains(body, name) {
+			t.Errorf("metric %s is missing", name)
+		}
+	}
+}
+
+func TestHandler_persistedQueries(t *testing.T) {
+	const query = "{ __typename }"
+	sum := sha256.Sum256([]byte(query))
+	extensions := fmt.Sprintf(`{"persistedQuery":{"version":1,"sha256Hash":"%s"}}`, hex.EncodeToString(sum[:]))
+	type testCase struct {
+		name             string
+		query            string
+		wantData         string
+		wantErrors       []string
+		wantCacheControl string
+	}
+	testCases := []testCase{
+		{"hash only before registered", "", "null", []string{"PersistedQueryNotFound"}, "no-store"},
+		{"register", query, `{"__typename":"Query"}`, nil, "private, max-age=30"},
+		{"hash only after registered", "", `{"__typename":"Query"}`, nil, "private, max-age=30"},
+	}
+	h := Handler(WithPersistedQueries(10), WithCache(10, time.Second*30))
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := url.Values{"extensions": {extensions}}
+			if tc.query != "" {
+				params.Set("query", tc.query)
+			}
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/extension/query?"+params.Encode(), nil)
+			req.Header.Set("authorization", "Bearer 0xdeadbeaf")
+			h.ServeHTTP(rec, req)
+			if got := rec.Header().Get("cache-control"); got != tc.wantCacheControl {
+				t.Errorf("Cache-Control: got=%q want=%q", got, tc.wantCacheControl)
+			}
+			if got := rec.Header().Get("vary"); got != "Authorization" {
+				t.Errorf("Vary: got=%q want=%q", got, "Authorization")
+			}
+			var body struct {
+				Data   json.RawMessage
+				Errors []struct{ Message string }
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if got := string(body.Data); got != tc.wantData {
+				t.Errorf("data: got=%s want=%s", got, tc.wantData)
+			}
+			var gotErrors []string
+			for _, e := range body.Errors {
+				gotErrors = append(gotErrors, e.Message)
+			}
+			if diff := cmp.Diff(gotErrors, tc.wantErrors); diff != "" {
+				t.Errorf("errors (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHandler_cacheControl(t *testing.T) {
+	type testCase struct {
+		name             string
+		opts             []Option
+		method           string
+		query            string
+		wantCacheControl string
+		wantVary         string
+	}
+	testCases := []testCase{
+		{"GET", []Option{WithCache(10, time.Minute)}, http.MethodGet, "{ __typename }", "private, max-age=60", "Authorization"},
+		{"GET without cache TTL", []Option{WithCache(10, 0)}, http.MethodGet, "{ __typename }", "no-store", "Authorization"},
+		{"GET without cache", []Option{WithCache(0, 0)}, http.MethodGet, "{ __typename }", "no-store", "Authorization"},
+		{"GET failed", []Option{WithCache(10, time.Minute)}, http.MethodGet, "{ unknownField }", "no-store", "Authorization"},
+		{"POST", []Option{WithCache(10, time.Minute)}, http.MethodPost, "{ __typename }", "", ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var req *http.Request
+			if tc.method == http.MethodGet {
+				req = httptest.NewRequest(http.MethodGet, "/extension/query?"+url.Values{"query": {tc.query}}.Encode(), nil)
+			} else {
+				reqBody, err := json.Marshal(map[string]string{"query": tc.query})
+				if err != nil {
+					t.Fatal(err)
+				}
+				req = httptest.NewRequest(http.MethodPost, "/extension/query", strings.NewReader(string(reqBody)))
+				req.Header.Set("content-type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+			Handler(tc.opts...).ServeHTTP(rec, req)
+			if got := rec.Header().Get("cache-control"); got != tc.wantCacheControl {
+				t.Errorf("Cache-Control: got=%q want=%q", got, tc.wantCacheControl)
+			}
+			if got := rec.Header().Get("vary"); got != tc.wantVary {
+				t.Errorf("Vary: got=%q want=%q", got, tc.wantVary)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v47/github"
+)
+
+const upstreamCheckTimeout = time.Second * 10
+
+var (
+	ErrInvalidUpstreamURL = errors.New("invalid upstream URL")
+	ErrUpstreamNotReached = errors.New("upstream GitHub API is not reachable")
+)
+
+// UpstreamInfo describes the GitHub instance the proxy calls.
+type UpstreamInfo struct {
+	APIBaseURL string
+	// Enterprise is true if the upstream is GitHub Enterprise Server.
+	Enterprise bool
+	// Version is the installed version of GitHub Enterprise Server. It is empty for GitHub.com or if it cannot be detected.
+	Version string
+}
+
+func (i *UpstreamInfo) String() string {
+	if !i.Enterprise {
+		return fmt.Sprintf("GitHub.com (%s)", i.APIBaseURL)
+	}
+	if i.Version == "" {
+		return fmt.Sprintf("GitHub Enterprise Server (%s)", i.APIBaseURL)
+	}
+	return fmt.Sprintf("GitHub Enterprise Server %s (%s)", i.Version, i.APIBaseURL)
+}
+
+// DefaultUploadURL returns the upload URL of GitHub Enterprise Server that serves the API at apiBaseURL.
+func DefaultUploadURL(apiBaseURL string) (string, error) {
+	u, err := parseUpstreamURL(apiBaseURL)
+	if err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/api/uploads/"}).String(), nil
+}
+
+func parseUpstreamURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUpstreamURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("%w: scheme must be http or https: %q", ErrInvalidUpstreamURL, s)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%w: host is missing: %q", ErrInvalidUpstreamURL, s)
+	}
+	return u, nil
+}
+
+func (o *options) newGitHubClient(httpClient *http.Client) (*github.Client, error) {
+	if o.apiBaseURL == "" {
+		return github.NewClient(httpClient), nil
+	}
+	return github.NewEnterpriseClient(o.apiBaseURL, o.uploadURL, httpClient)
+}
+
+// detectUpstream validates the upstream URLs and asks /meta of GitHub Enterprise Server whether it is reachable and which version it runs.
+//
+// GitHub.com is not probed so that the startup neither depends on it nor spends the anonymous quota.
+func detectUpstream(ctx context.Context, o *options) (*UpstreamInfo, error) {
+	if o.apiBaseURL == "" {
+		client, err := o.newGitHubClient(http.DefaultClient)
+		if err != nil {
+			return nil, err
+		}
+		return &UpstreamInfo{APIBaseURL: client.BaseURL.String()}, nil
+	}
+	if _, err := parseUpstreamURL(o.apiBaseURL); err != nil {
+		return nil, err
+	}
+	if _, err := parseUpstreamURL(o.uploadURL); err != nil {
+		return nil, err
+	}
+	client, err := o.newGitHubClient(&http.Client{Timeout: upstreamCheckTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidUpstreamURL, err)
+	}
+	info := &UpstreamInfo{APIBaseURL: client.BaseURL.String(), Enterprise: true}
+	req, err := client.NewRequest(http.MethodGet, "meta", nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta struct {
+		InstalledVersion string `json:"installed_version"`
+	}
+	if _, err := client.Do(ctx, req, &meta); err != nil {
+		var errResp *github.ErrorResponse
+		if errors.As(err, &errResp) {
+			// the upstream responded; /meta may require authentication on private mode instances
+			return info, nil
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUpstreamNotReached, err)
+	}
+	info.Version = meta.InstalledVersion
+	return info, nil
+}
@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectUpstream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.URL.Path {
+		case "/api/v3/meta":
+			fmt.Fprint(w, `{"installed_version":"3.6.1"}`)
+		case "/private/api/v3/meta":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"Must authenticate to access this API."}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	type testCase struct {
+		name       string
+		apiBaseURL string
+		uploadURL  string
+		want       *UpstreamInfo
+		wantErr    error
+	}
+	testCases := []testCase{
+		{"enterprise", srv.URL, srv.URL, &UpstreamInfo{APIBaseURL: srv.URL + "/api/v3/", Enterprise: true, Version: "3.6.1"}, nil},
+		{"private mode", srv.URL + "/private", srv.URL, &UpstreamInfo{APIBaseURL: srv.URL + "/private/api/v3/", Enterprise: true}, nil},
+		{"malformed", "ftp://ghes.example.com", srv.URL, nil, ErrInvalidUpstreamURL},
+		{"no host", "https:///api/v3", srv.URL, nil, ErrInvalidUpstreamURL},
+		{"unreachable", closed.URL, closed.URL, nil, ErrUpstreamNotReached},
+		{"GitHub.com is not probed", "", "", &UpstreamInfo{APIBaseURL: "https://api.github.com/"}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []Option
+			if tc.apiBaseURL != "" {
+				opts = append(opts, WithGitHubEnterprise(tc.apiBaseURL, tc.uploadURL))
+			}
+			o := newOptions(opts)
+			got, err := detectUpstream(context.Background(), o)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error: got=%v want=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("upstream (-got, +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDefaultUploadURL(t *testing.T) {
+	got, err := DefaultUploadURL("https://ghes.example.com/api/v3/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://ghes.example.com/api/uploads/"; got != want {
+		t.Errorf("got=%q want=%q", got, want)
+	}
+}
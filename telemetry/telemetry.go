@@ -0,0 +1,81 @@
+// Package telemetry names the GraphQL operations and the upstream REST endpoints alike in metrics, traces and access logs.
+package telemetry
+
+import (
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// AnonymousOperation is the name of the operations without a name.
+const AnonymousOperation = "anonymous"
+
+// OperationName returns the name of the operation to execute, or empty if it is anonymous.
+func OperationName(oc *graphql.OperationContext) string {
+	if oc.OperationName != "" {
+		return oc.OperationName
+	}
+	if oc.Operation != nil {
+		return oc.Operation.Name
+	}
+	return ""
+}
+
+// OtherEndpoint is the endpoint template of the paths that match none of the known endpoints.
+const OtherEndpoint = "other"
+
+// endpointTemplates lists the GitHub REST API endpoints the proxy calls.
+// The parameters are named in braces and match any single segment.
+var endpointTemplates = [][]string{
+	splitPath("/app/installations/{id}/access_tokens"),
+	splitPath("/meta"),
+	splitPath("/orgs/{org}"),
+	splitPath("/orgs/{org}/installation"),
+	splitPath("/orgs/{org}/settings/billing/actions"),
+	splitPath("/orgs/{org}/settings/billing/shared-storage"),
+	splitPath("/orgs/{org}/teams/{team}/memberships/{user}"),
+	splitPath("/rate_limit"),
+	splitPath("/repos/{owner}/{repo}/actions/artifacts"),
+	splitPath("/repos/{owner}/{repo}/actions/artifacts/{id}"),
+	splitPath("/repos/{owner}/{repo}/installation"),
+	splitPath("/user"),
+	splitPath("/user/orgs"),
+	splitPath("/users/{user}"),
+	splitPath("/users/{user}/installation"),
+}
+
+// EndpointTemplate returns the template of GitHub REST API path such as /orgs/{org}.
+//
+// The paths other than the known endpoints are reported as OtherEndpoint
+// so that neither the names of teams and users nor unexpected paths make up distinct labels and span names.
+func EndpointTemplate(path string) string {
+	segments := splitPath(strings.TrimPrefix(path, "/api/v3"))
+	for _, template := range endpointTemplates {
+		if matchTemplate(template, segments) {
+			return "/" + strings.Join(template, "/")
+		}
+	}
+	return OtherEndpoint
+}
+
+func matchTemplate(template, segments []string) bool {
+	if len(template) != len(segments) {
+		return false
+	}
+	for i, want := range template {
+		if strings.HasPrefix(want, "{") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segments[i] != want {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
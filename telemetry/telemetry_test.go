@@ -0,0 +1,31 @@
+package telemetry_test
+
+import (
+	"testing"
+
+	"github.com/aereal/github-graphql-proxy/telemetry"
+)
+
+func TestEndpointTemplate(t *testing.T) {
+	testCases := map[string]string{
+		"/api/v3/orgs/test-org":                             "/orgs/{org}",
+		"/orgs/test-org/settings/billing/actions":           "/orgs/{org}/settings/billing/actions",
+		"/repos/test-org/test-repo/actions/artifacts":       "/repos/{owner}/{repo}/actions/artifacts",
+		"/repos/test-org/test-repo/actions/artifacts/12345": "/repos/{owner}/{repo}/actions/artifacts/{id}",
+		"/app/installations/42/access_tokens":               "/app/installations/{id}/access_tokens",
+		"/users/octocat":                                    "/users/{user}",
+		"/meta":                                             "/meta",
+		"/orgs/test-org/teams/finance/memberships/octocat":  "/orgs/{org}/teams/{team}/memberships/{user}",
+		"/api/v3/user/orgs":                                 "/user/orgs",
+		"/orgs/test-org/teams/finance":                      telemetry.OtherEndpoint,
+		"/repos/test-org/test-repo/contents/README.md":      telemetry.OtherEndpoint,
+		"/orgs//settings/billing/actions":                   telemetry.OtherEndpoint,
+	}
+	for path, want := range testCases {
+		t.Run(path, func(t *testing.T) {
+			if got := telemetry.EndpointTemplate(path); got != want {
+				t.Errorf("got=%q want=%q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/aereal/github-graphql-proxy/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/aereal/github-graphql-proxy/tracing"
+
+// Extension starts spans for each operation and each resolver.
+//
+// It uses the global tracer provider if TracerProvider is nil.
+type Extension struct {
+	TracerProvider trace.TracerProvider
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Extension{}
+
+func (Extension) ExtensionName() string {
+	return "OpenTelemetry"
+}
+
+func (Extension) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (e Extension) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	oc := graphql.GetOperationContext(ctx)
+	name := telemetry.OperationName(oc)
+	var attrs []attribute.KeyValue
+	if oc.Operation != nil {
+		attrs = append(attrs, attribute.String("graphql.operation.type", string(oc.Operation.Operation)))
+	}
+	attrs = append(attrs, attribute.String("graphql.operation.name", name))
+	if name == "" {
+		name = telemetry.AnonymousOperation
+	}
+	ctx, span := e.tracer().Start(ctx, "graphql.operation "+name, trace.WithAttributes(attrs...))
+	defer span.End()
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+func (e Extension) InterceptField(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	ctx, span := e.tracer().Start(ctx, fmt.Sprintf("graphql.resolve %s.%s", fc.Object, fc.Field.Name),
+		trace.WithAttributes(
+			attribute.String("graphql.field.path", fc.Path().String()),
+			attribute.String("graphql.field.name", fc.Field.Name),
+			attribute.String("graphql.field.parent", fc.Object),
+		))
+	defer span.End()
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
+
+func (e Extension) tracer() trace.Tracer {
+	tp := e.TracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(instrumentationName)
+}
@@ -0,0 +1,147 @@
+package tracing_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	githubgraphqlproxy "github.com/aereal/github-graphql-proxy"
+	"github.com/aereal/github-graphql-proxy/resolvers"
+	"github.com/aereal/github-graphql-proxy/tracing"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v47/github"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	githubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"plan":{"name":"enterprise"}}`)
+	}))
+	defer githubSrv.Close()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	githubClient, err := github.NewEnterpriseClient(githubSrv.URL, githubSrv.URL, &http.Client{Transport: tracing.Transport(githubSrv.Client().Transport, tp)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := handler.New(githubgraphqlproxy.NewExecutableSchema(githubgraphqlproxy.Config{Resolvers: resolvers.New(githubClient)}))
+	h.AddTransport(transport.POST{})
+	h.Use(tracing.Extension{TracerProvider: tp})
+	srv := httptest.NewServer(tracing.Handler(h, "extension/query", tp))
+	defer srv.Close()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, strings.NewReader(`{"query":"query myQuery { test__organization(login: \"test-org\") { plan { name } } }"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("traceparent", fmt.Sprintf("00-%s-00f067aa0ba902b7-01", traceID))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	var names []string
+	for _, span := range recorder.Ended() {
+		names = append(names, span.Name())
+		if strings.HasPrefix(span.Name(), "GitHub ") {
+			var gotURL string
+			for _, attr := range span.Attributes() {
+				if attr.Key == "http.url" {
+					gotURL = attr.Value.AsString()
+				}
+			}
+			if wantURL := githubSrv.URL + "/api/v3/orgs/test-org"; gotURL != wantURL {
+				t.Errorf("http.url of %q: got=%q want=%q", span.Name(), gotURL, wantURL)
+			}
+		}
+		if got := span.SpanContext().TraceID().String(); got != traceID {
+			t.Errorf("trace ID of %q: got=%s want=%s", span.Name(), got, traceID)
+		}
+	}
+	sort.Strings(names)
+	want := []string{
+		"GitHub GET /orgs/{org}",
+		"extension/query",
+		"graphql.operation myQuery",
+		"graphql.resolve Organization.plan",
+		"graphql.resolve Query.test__organization",
+	}
+	if diff := cmp.Diff(names, want); diff != "" {
+		t.Errorf("spans (-got, +want):\n%s", diff)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	type testCase struct {
+		exporter string
+		wantErr  bool
+	}
+	testCases := []testCase{
+		{tracing.ExporterNone, false},
+		{tracing.ExporterStdout, false},
+		{"zipkin", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.exporter, func(t *testing.T) {
+			shutdown, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tc.exporter, Writer: new(strings.Builder)})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error: got=%v wantErr=%v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if err := shutdown(context.Background()); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestTransport_doesNotPropagate(t *testing.T) {
+	// installs the global propagator that the transport must not use
+	if _, err := tracing.Setup(context.Background(), tracing.Config{Exporter: tracing.ExporterNone}); err != nil {
+		t.Fatal(err)
+	}
+	var gotHeader http.Header
+	githubSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+	}))
+	defer githubSrv.Close()
+	tp := sdktrace.NewTracerProvider()
+	member, err := baggage.NewMember("tenant", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bag, err := baggage.New(member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, span := tp.Tracer("test").Start(baggage.ContextWithBaggage(context.Background(), bag), "caller")
+	defer span.End()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, githubSrv.URL+"/orgs/test-org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := (&http.Client{Transport: tracing.Transport(githubSrv.Client().Transport, tp)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	for _, name := range []string{"traceparent", "tracestate", "baggage"} {
+		if v := gotHeader.Get(name); v != "" {
+			t.Errorf("%s header is sent to GitHub: %q", name, v)
+		}
+	}
+}